@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
 	"go-server-starter/internal/enum"
 	"go-server-starter/internal/handler"
 	"go-server-starter/internal/middleware"
@@ -12,18 +13,25 @@ import (
 	"go-server-starter/internal/router"
 	"go-server-starter/internal/seed"
 	"go-server-starter/internal/service"
+	"go-server-starter/pkg/asyn_queue"
 	"go-server-starter/pkg/auth"
 	"go-server-starter/pkg/database"
 	"go-server-starter/pkg/jwt"
 	"go-server-starter/pkg/logger"
+	"go-server-starter/pkg/notifier"
+	"go-server-starter/pkg/oauth"
+	"go-server-starter/pkg/password"
 	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/sender"
 	"go-server-starter/pkg/snowflake"
+	"go-server-starter/pkg/tenant"
 	"go-server-starter/pkg/translator"
 	"go-server-starter/pkg/validator"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
 	"go.uber.org/zap"
 )
 
@@ -38,11 +46,19 @@ type App struct {
 	translator *translator.Translator
 	ratelimit  *middleware.RateLimit
 	jwt        *jwt.JWT
+	sender     sender.Sender
+	notifier   notifier.Notifier
+	hasher     *password.Hasher
+	oauth      *oauth.Registry
 	auth       auth.Auth
+	tenant     tenant.Resolver
 	handler    handler.Handler
 	repo       repo.Repo
 	service    service.Service
 	seed       seed.Seed
+	queue      *asyn_queue.Client
+	worker     *asyn_queue.Server
+	scheduler  *asyn_queue.Scheduler
 }
 
 func NewApp(config *config.Config, logger *zap.Logger) *App {
@@ -61,6 +77,8 @@ func (a *App) Start() error {
 	// 初始化gin引擎
 	a.engine = gin.New()
 	a.engine.Use(gin.Recovery())
+	a.engine.Use(middleware.RequestID())
+	a.engine.Use(middleware.ReadPrimaryOnWrite())
 	a.engine.Use(middleware.ZapLogger(a.logger.Named("GIN")))
 	a.engine.Use(middleware.ZapRecovery(a.logger.Named("GIN-RECOVERY"), isDev))
 
@@ -105,8 +123,18 @@ func (a *App) Start() error {
 
 	// auto migrate
 	err = db.AutoMigrate(
+		&model.Permission{},
 		&model.UserRole{},
 		&model.User{},
+		&model.UserSession{},
+		&model.UserTOTP{},
+		&model.UserRecoveryCode{},
+		&model.UserIdentity{},
+		&model.APIKey{},
+		&model.Organization{},
+		&model.OrganizationMember{},
+		&model.AuditLog{},
+		&model.LoginEvent{},
 	)
 	if err != nil {
 		return err
@@ -143,11 +171,57 @@ func (a *App) Start() error {
 		return err
 	}
 
+	// 迁移和 seed 完成后再启用只读副本
+	if err := a.db.UseReplicas(); err != nil {
+		return err
+	}
+	a.db.StartReplicaCheck(a.config.Database.ReplicaMaxLag, a.config.Database.ReplicaCheckInterval, a.logger.Named("DB"))
+
 	// 初始化jwt
-	a.jwt = jwt.NewJWT(
+	jwt, err := jwt.NewJWT(
 		&a.config.JWT,
+		a.redis,
 		a.logger.Named("JWT"),
 	)
+	if err != nil {
+		return err
+	}
+	a.jwt = jwt
+
+	// 初始化sender
+	sender, err := sender.NewSender(a.config.Sender, a.logger.Named("SENDER"))
+	if err != nil {
+		return err
+	}
+	a.sender = sender
+
+	// 初始化通知
+	notifier, err := notifier.NewNotifier(a.config.Notifier, a.sender, a.logger.Named("NOTIFIER"))
+	if err != nil {
+		return err
+	}
+	a.notifier = notifier
+
+	// 初始化密码哈希
+	hasher, err := password.NewHasher(a.config.Password)
+	if err != nil {
+		return err
+	}
+	a.hasher = hasher
+
+	// 初始化第三方登录
+	oauthRegistry, err := oauth.NewRegistry(a.config.OAuth.Providers, a.logger.Named("OAUTH"))
+	if err != nil {
+		return err
+	}
+	a.oauth = oauthRegistry
+
+	// 初始化异步队列
+	queue, err := asyn_queue.NewClient(a.config.AsynQ, a.logger.Named("ASYNQ"))
+	if err != nil {
+		return err
+	}
+	a.queue = queue
 
 	// 初始化service
 	a.service = service.NewService(
@@ -156,11 +230,43 @@ func (a *App) Start() error {
 		a.jwt,
 		a.redis,
 		a.snowflake,
+		a.sender,
+		a.hasher,
+		a.oauth,
+		a.notifier,
+		a.queue,
 		a.repo,
 		a.logger.Named("SERVICE"),
 	)
+	// Redis 中没有账号状态标记时回源数据库
+	a.jwt.SetUserStatusLoader(a.service.UserStatus().LoadStatus)
+	// 初始化异步任务
+	a.worker = asyn_queue.NewServer(a.config.AsynQ, asyn_queue.ServerConfig{
+		Concurrency: a.config.AsynQ.Concurrency,
+		Queues:      map[string]int{"default": 1},
+	}, a.logger.Named("ASYNQ-WORKER"))
+	a.worker.HandleFunc(constant.TASK_TYPE_OF_AUDIT_LOG, a.service.Audit().HandleTask)
+	a.worker.HandleFunc(constant.TASK_TYPE_OF_LOGIN_NOTIFY, a.service.LoginEvent().HandleNotifyTask)
+	a.worker.HandleFunc(constant.TASK_TYPE_OF_ACCOUNT_ERASE, a.service.PersonalData().HandleEraseTask)
+	a.worker.HandleFunc(constant.TASK_TYPE_OF_TRASH_PURGE, a.service.Trash().HandlePurgeTask)
+	if err := a.worker.Start(); err != nil {
+		return err
+	}
+	// 初始化定时任务, 多实例时用 Unique 避免重复执行
+	a.scheduler = asyn_queue.NewScheduler(a.config.AsynQ, a.logger.Named("ASYNQ-SCHEDULER"))
+	if a.config.Trash.PurgeCron != "" {
+		purge := asynq.NewTask(constant.TASK_TYPE_OF_TRASH_PURGE, nil)
+		if err := a.scheduler.Register(a.config.Trash.PurgeCron, purge, asynq.Unique(time.Hour), asynq.MaxRetry(3)); err != nil {
+			return err
+		}
+	}
+	if err := a.scheduler.Start(); err != nil {
+		return err
+	}
 	// 初始化auth
-	a.auth = auth.NewAuth(a.service, a.logger.Named("AUTH"))
+	a.auth = auth.NewAuth(a.service, a.jwt, a.logger.Named("AUTH"))
+	// 初始化租户识别
+	a.tenant = tenant.NewResolver(a.service, &a.config.Tenant, a.logger.Named("TENANT"))
 	// 初始化handler
 	a.handler = handler.NewHandler(
 		a.service,
@@ -174,8 +280,10 @@ func (a *App) Start() error {
 	router := router.NewRouter(
 		a.handler,
 		a.engine.Group(serverConfig.APIPrefix),
+		a.engine.Group("/"),
 		a.jwt,
 		a.auth,
+		a.tenant,
 		a.ratelimit,
 	)
 	router.SetupRoutes()
@@ -206,6 +314,20 @@ func (a *App) Shutdown() error {
 		}
 	}
 
+	if a.scheduler != nil {
+		a.scheduler.Shutdown()
+	}
+
+	if a.worker != nil {
+		a.worker.Shutdown()
+	}
+
+	if a.queue != nil {
+		if err := a.queue.Close(); err != nil {
+			a.logger.Error("Failed to close queue", zap.Error(err))
+		}
+	}
+
 	if a.db != nil {
 		if err := a.db.Close(); err != nil {
 			a.logger.Error("Failed to close database", zap.Error(err))
@@ -19,6 +19,19 @@ type Config struct {
 	AsynQ      AsynQConfig      `mapstructure:"asynQ"`
 	Logger     LoggerConfig     `mapstructure:"logger"`
 	GormLogger GormLoggerConfig `mapstructure:"gormLogger"`
+	VerifyCode VerifyCodeConfig `mapstructure:"verifyCode"`
+	Sender     SenderConfig     `mapstructure:"sender"`
+	Password   PasswordConfig   `mapstructure:"password"`
+	Session    SessionConfig    `mapstructure:"session"`
+	MFA        MFAConfig        `mapstructure:"mfa"`
+	OAuth      OAuthConfig      `mapstructure:"oauth"`
+	APIKey     APIKeyConfig     `mapstructure:"apiKey"`
+	Role       RoleConfig       `mapstructure:"role"`
+	Tenant     TenantConfig     `mapstructure:"tenant"`
+	Login      LoginConfig      `mapstructure:"login"`
+	Notifier   NotifierConfig   `mapstructure:"notifier"`
+	Privacy    PrivacyConfig    `mapstructure:"privacy"`
+	Trash      TrashConfig      `mapstructure:"trash"`
 	Mode       enum.ServerMode  `mapstructure:"-"`
 }
 
@@ -95,6 +108,19 @@ func setDefaultConfig(v *viper.Viper) {
 	setDefaultsFromStruct(v, "asynQ", DefaultConfig.AsynQ)
 	setDefaultsFromStruct(v, "logger", DefaultConfig.Logger)
 	setDefaultsFromStruct(v, "gormLogger", DefaultConfig.GormLogger)
+	setDefaultsFromStruct(v, "verifyCode", DefaultConfig.VerifyCode)
+	setDefaultsFromStruct(v, "sender", DefaultConfig.Sender)
+	setDefaultsFromStruct(v, "password", DefaultConfig.Password)
+	setDefaultsFromStruct(v, "session", DefaultConfig.Session)
+	setDefaultsFromStruct(v, "mfa", DefaultConfig.MFA)
+	setDefaultsFromStruct(v, "oauth", DefaultConfig.OAuth)
+	setDefaultsFromStruct(v, "apiKey", DefaultConfig.APIKey)
+	setDefaultsFromStruct(v, "role", DefaultConfig.Role)
+	setDefaultsFromStruct(v, "tenant", DefaultConfig.Tenant)
+	setDefaultsFromStruct(v, "login", DefaultConfig.Login)
+	setDefaultsFromStruct(v, "notifier", DefaultConfig.Notifier)
+	setDefaultsFromStruct(v, "privacy", DefaultConfig.Privacy)
+	setDefaultsFromStruct(v, "trash", DefaultConfig.Trash)
 }
 
 func setDefaultsFromStruct(v *viper.Viper, prefix string, structValue interface{}) {
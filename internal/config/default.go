@@ -1,6 +1,7 @@
 package config
 
 import (
+	"go-server-starter/internal/enum"
 	"time"
 )
 
@@ -16,7 +17,15 @@ var DefaultConfig = Config{
 	JWT: JWTConfig{
 		Issuer:      "go-server-starter",
 		TokenSecret: "tokenSecret@123",
-		TokenExpires: MultiTokenExpireConfig{
+		AccessTokenExpires: MultiTokenExpireConfig{
+			Web:             15 * time.Minute,
+			Desktop:         30 * time.Minute,
+			Mobile:          30 * time.Minute,
+			ChromeExtension: 30 * time.Minute,
+			API:             1 * time.Hour,
+			Default:         15 * time.Minute,
+		},
+		RefreshTokenExpires: MultiTokenExpireConfig{
 			Web:             24 * time.Hour,
 			Desktop:         15 * 24 * time.Hour,
 			Mobile:          15 * 24 * time.Hour,
@@ -24,19 +33,31 @@ var DefaultConfig = Config{
 			API:             2 * 24 * time.Hour,
 			Default:         1 * 24 * time.Hour,
 		},
+		Audience: MultiAudienceConfig{
+			Web:             "go-server-starter:web",
+			Desktop:         "go-server-starter:desktop",
+			Mobile:          "go-server-starter:mobile",
+			ChromeExtension: "go-server-starter:chrome-extension",
+			API:             "go-server-starter:api",
+			Default:         "go-server-starter",
+		},
+		Leeway:                    30 * time.Second,
+		ImpersonationTokenExpires: 15 * time.Minute,
 	},
 	Database: DatabaseConfig{
-		Host:            "localhost",
-		Port:            3306,
-		Username:        "root",
-		Password:        "root",
-		Name:            "test_db",
-		MaxIdleConns:    10,
-		MaxOpenConns:    100,
-		ConnMaxLifetime: 10 * time.Second,
-		Timezone:        "UTC",
-		Charset:         "utf8mb4",
-		ParseTime:       true,
+		Host:                 "localhost",
+		Port:                 3306,
+		Username:             "root",
+		Password:             "root",
+		Name:                 "test_db",
+		MaxIdleConns:         10,
+		MaxOpenConns:         100,
+		ConnMaxLifetime:      10 * time.Second,
+		Timezone:             "UTC",
+		Charset:              "utf8mb4",
+		ParseTime:            true,
+		ReplicaMaxLag:        5 * time.Second,
+		ReplicaCheckInterval: 10 * time.Second,
 	},
 	Redis: RedisConfig{
 		Host:     "localhost",
@@ -68,4 +89,72 @@ var DefaultConfig = Config{
 		SkipCallerLookup:          false,
 		IgnoreRecordNotFoundError: true,
 	},
+	VerifyCode: VerifyCodeConfig{
+		Length:           6,
+		Expire:           5 * time.Minute,
+		ResendInterval:   60 * time.Second,
+		MaxSendPerTarget: 10,
+		MaxSendPerIP:     20,
+		MaxAttempts:      5,
+	},
+	Sender: SenderConfig{
+		Driver:   "log",
+		FilePath: "./logs/sender.log",
+	},
+	Password: PasswordConfig{
+		Algorithm:         "bcrypt",
+		BcryptCost:        10,
+		Argon2Memory:      64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 2,
+		Argon2KeyLength:   32,
+		SaltSize:          16,
+	},
+	Session: SessionConfig{
+		MaxSessions: MultiSessionLimitConfig{},
+	},
+	MFA: MFAConfig{
+		Issuer:            "go-server-starter",
+		Skew:              1,
+		RecoveryCodeCount: 10,
+		ChallengeExpire:   5 * time.Minute,
+		MaxAttempts:       5,
+	},
+	OAuth: OAuthConfig{
+		StateExpire: 10 * time.Minute,
+	},
+	APIKey: APIKeyConfig{
+		Prefix:           "gss",
+		MaxPerUser:       20,
+		LastUsedInterval: 1 * time.Minute,
+	},
+	Role: RoleConfig{
+		Inherits: map[enum.RoleCode][]enum.RoleCode{
+			enum.RoleCodeSuperAdmin: {enum.RoleCodeAdmin},
+			enum.RoleCodeUserSvip:   {enum.RoleCodeUserVip},
+			enum.RoleCodeUserVip:    {enum.RoleCodeUser},
+		},
+	},
+	Tenant: TenantConfig{
+		Header: "X-Tenant",
+	},
+	Login: LoginConfig{
+		IPv4PrefixLen: 24,
+		IPv6PrefixLen: 48,
+		StepUpExpire:  10 * time.Minute,
+	},
+	Notifier: NotifierConfig{
+		Driver: "log",
+	},
+	Privacy: PrivacyConfig{
+		DeletionGracePeriod: 30 * 24 * time.Hour,
+		EraseMode:           "anonymize",
+	},
+	Trash: TrashConfig{
+		PurgeCron: "0 3 * * *",
+		Retention: 30 * 24 * time.Hour,
+		Retentions: map[string]time.Duration{
+			"user_sessions": 7 * 24 * time.Hour,
+		},
+	},
 }
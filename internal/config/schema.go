@@ -25,8 +25,51 @@ type JWTConfig struct {
 	// 签发者
 	Issuer string `mapstructure:"issuer"` // 签发者
 	// token
-	TokenSecret  string                 `mapstructure:"tokenSecret"`  // 令牌密钥
-	TokenExpires MultiTokenExpireConfig `mapstructure:"tokenExpires"` // 令牌过期时间
+	TokenSecret         string                 `mapstructure:"tokenSecret"`         // 令牌密钥, 未配置非对称密钥时使用 HS256
+	AccessTokenExpires  MultiTokenExpireConfig `mapstructure:"accessTokenExpires"`  // 访问令牌过期时间
+	RefreshTokenExpires MultiTokenExpireConfig `mapstructure:"refreshTokenExpires"` // 刷新令牌过期时间
+	// 非对称签名
+	SigningKeyID string         `mapstructure:"signingKeyId"` // 当前用于签名的密钥ID
+	Keys         []JWTKeyConfig `mapstructure:"keys"`         // 密钥列表, 轮换期间新旧密钥同时用于验证
+	// 验证
+	Audience MultiAudienceConfig `mapstructure:"audience"` // 每种设备类型的受众
+	Leeway   time.Duration       `mapstructure:"leeway"`   // 时钟偏差容忍
+	// 模拟登录
+	ImpersonationTokenExpires time.Duration `mapstructure:"impersonationTokenExpires"` // 模拟登录令牌过期时间, 不可刷新
+}
+
+type JWTKeyConfig struct {
+	KID            string `mapstructure:"kid"`            // 密钥ID, 写入令牌头部的 kid
+	Algorithm      string `mapstructure:"algorithm"`      // RS256 | ES256 | EdDSA
+	PrivateKeyFile string `mapstructure:"privateKeyFile"` // 私钥 PEM 文件, 签名密钥必须配置
+	PublicKeyFile  string `mapstructure:"publicKeyFile"`  // 公钥 PEM 文件, 未配置时从私钥推导
+}
+
+// 为空时不签发也不校验 aud
+type MultiAudienceConfig struct {
+	Web             string `mapstructure:"web"`             // 网页
+	Desktop         string `mapstructure:"desktop"`         // 桌面软件
+	Mobile          string `mapstructure:"mobile"`          // 移动端APP
+	ChromeExtension string `mapstructure:"chromeExtension"` // Chrome扩展
+	API             string `mapstructure:"api"`             // API
+	Default         string `mapstructure:"default"`         // 默认
+}
+
+func (m *MultiAudienceConfig) Get(deviceType enum.DeviceType) string {
+	switch deviceType {
+	case enum.DeviceTypeWeb:
+		return m.Web
+	case enum.DeviceTypeDesktop:
+		return m.Desktop
+	case enum.DeviceTypeMobile:
+		return m.Mobile
+	case enum.DeviceTypeChromeExtension:
+		return m.ChromeExtension
+	case enum.DeviceTypeApi:
+		return m.API
+	default:
+		return m.Default
+	}
 }
 
 type MultiTokenExpireConfig struct {
@@ -55,6 +98,11 @@ func (m *MultiTokenExpireConfig) Get(deviceType enum.DeviceType) time.Duration {
 	}
 }
 
+// Max 返回所有设备类型中最长的过期时间
+func (m *MultiTokenExpireConfig) Max() time.Duration {
+	return max(m.Web, m.Desktop, m.Mobile, m.ChromeExtension, m.API, m.Default)
+}
+
 type LoggerConfig struct {
 	Level         string `mapstructure:"level"`         // 日志级别
 	FileDir       string `mapstructure:"fileDir"`       // 日志文件目录
@@ -77,6 +125,21 @@ type DatabaseConfig struct {
 	Timezone        string        `mapstructure:"timezone"`  // timezone configuration
 	Charset         string        `mapstructure:"charset"`   // character set (primarily for MySQL)
 	ParseTime       bool          `mapstructure:"parseTime"` // parse time (for MySQL)
+	// 只读副本, 配置后查询默认走副本, 写入和事务走主库
+	Replicas             []ReplicaConfig `mapstructure:"replicas"`
+	ReplicaMaxLag        time.Duration   `mapstructure:"replicaMaxLag"`        // 复制延迟超过该值的副本暂停使用, 恢复后重新启用
+	ReplicaCheckInterval time.Duration   `mapstructure:"replicaCheckInterval"` // 副本健康检查间隔
+}
+
+// ReplicaConfig 只读副本, 账号密码和连接池为空时使用主库的配置
+type ReplicaConfig struct {
+	Host            string        `mapstructure:"host"`
+	Port            int           `mapstructure:"port"`
+	Username        string        `mapstructure:"username"`
+	Password        string        `mapstructure:"password"`
+	MaxIdleConns    int           `mapstructure:"maxIdleConns"`
+	MaxOpenConns    int           `mapstructure:"maxOpenConns"`
+	ConnMaxLifetime time.Duration `mapstructure:"connMaxLifetime"`
 }
 
 type GormLoggerConfig struct {
@@ -90,3 +153,142 @@ type AsynQConfig struct {
 	RedisConfig RedisConfig `mapstructure:"redisConfig"` // Redis配置
 	Concurrency int         `mapstructure:"concurrency"` // 并发数
 }
+
+type VerifyCodeConfig struct {
+	Length           int           `mapstructure:"length"`           // 验证码长度
+	Expire           time.Duration `mapstructure:"expire"`           // 验证码有效期
+	ResendInterval   time.Duration `mapstructure:"resendInterval"`   // 同一目标重发间隔
+	MaxSendPerTarget int           `mapstructure:"maxSendPerTarget"` // 同一目标每天最大发送次数
+	MaxSendPerIP     int           `mapstructure:"maxSendPerIP"`     // 同一IP每小时最大发送次数
+	MaxAttempts      int           `mapstructure:"maxAttempts"`      // 最大错误尝试次数, 超过后验证码失效
+}
+
+type SenderConfig struct {
+	Driver   string `mapstructure:"driver"`   // 发送驱动: log, file
+	FilePath string `mapstructure:"filePath"` // file 驱动的输出文件
+}
+
+type PasswordConfig struct {
+	Algorithm         string `mapstructure:"algorithm"`         // 哈希算法: bcrypt, argon2id, 修改后旧密码在下次登录时自动升级
+	BcryptCost        int    `mapstructure:"bcryptCost"`        // bcrypt 成本
+	Argon2Memory      uint32 `mapstructure:"argon2Memory"`      // argon2id 内存(KiB)
+	Argon2Iterations  uint32 `mapstructure:"argon2Iterations"`  // argon2id 迭代次数
+	Argon2Parallelism uint8  `mapstructure:"argon2Parallelism"` // argon2id 并行度
+	Argon2KeyLength   uint32 `mapstructure:"argon2KeyLength"`   // argon2id 输出长度
+	SaltSize          int    `mapstructure:"saltSize"`          // 盐的字节数
+}
+
+type SessionConfig struct {
+	MaxSessions MultiSessionLimitConfig `mapstructure:"maxSessions"` // 每种设备类型的最大并发会话数, 超出时踢出最早的会话
+}
+
+// 0 表示不限制
+type MultiSessionLimitConfig struct {
+	Web             int `mapstructure:"web"`             // 网页
+	Desktop         int `mapstructure:"desktop"`         // 桌面软件
+	Mobile          int `mapstructure:"mobile"`          // 移动端APP
+	ChromeExtension int `mapstructure:"chromeExtension"` // Chrome扩展
+	API             int `mapstructure:"api"`             // API
+	Default         int `mapstructure:"default"`         // 默认
+}
+
+func (m *MultiSessionLimitConfig) Get(deviceType enum.DeviceType) int {
+	switch deviceType {
+	case enum.DeviceTypeWeb:
+		return m.Web
+	case enum.DeviceTypeDesktop:
+		return m.Desktop
+	case enum.DeviceTypeMobile:
+		return m.Mobile
+	case enum.DeviceTypeChromeExtension:
+		return m.ChromeExtension
+	case enum.DeviceTypeApi:
+		return m.API
+	default:
+		return m.Default
+	}
+}
+
+type MFAConfig struct {
+	Issuer            string          `mapstructure:"issuer"`            // 认证器 App 中显示的签发者
+	Skew              int             `mapstructure:"skew"`              // 允许前后偏差的时间步数
+	RecoveryCodeCount int             `mapstructure:"recoveryCodeCount"` // 恢复码数量
+	ChallengeExpire   time.Duration   `mapstructure:"challengeExpire"`   // 两步登录挑战有效期
+	MaxAttempts       int             `mapstructure:"maxAttempts"`       // 每个挑战最大尝试次数
+	RequiredRoles     []enum.RoleCode `mapstructure:"requiredRoles"`     // 必须开启两步验证的角色
+}
+
+// RoleConfig 角色继承关系, 拥有某个角色即拥有其继承的所有角色
+type RoleConfig struct {
+	Inherits map[enum.RoleCode][]enum.RoleCode `mapstructure:"inherits"` // 角色 -> 直接继承的角色
+}
+
+type OAuthConfig struct {
+	StateExpire time.Duration         `mapstructure:"stateExpire"` // 授权请求 state 有效期
+	Providers   []OAuthProviderConfig `mapstructure:"providers"`   // 第三方登录提供方
+}
+
+type OAuthProviderConfig struct {
+	Name         string   `mapstructure:"name"`         // 名称, 用于路由, 如 google
+	Type         string   `mapstructure:"type"`         // oidc | oauth2
+	ClientID     string   `mapstructure:"clientId"`     // 客户端ID
+	ClientSecret string   `mapstructure:"clientSecret"` // 客户端密钥
+	RedirectURL  string   `mapstructure:"redirectUrl"`  // 回调地址
+	Scopes       []string `mapstructure:"scopes"`       // 授权范围
+	// oidc 通过 discovery 获取端点
+	Issuer string `mapstructure:"issuer"` // 签发者
+	// oauth2 需要手动配置端点
+	AuthURL     string `mapstructure:"authUrl"`     // 授权地址
+	TokenURL    string `mapstructure:"tokenUrl"`    // 令牌地址
+	UserInfoURL string `mapstructure:"userInfoUrl"` // 用户信息地址
+	// 用户信息字段映射, 默认使用 oidc 标准字段
+	SubjectField string `mapstructure:"subjectField"` // 默认 sub
+	EmailField   string `mapstructure:"emailField"`   // 默认 email
+	NameField    string `mapstructure:"nameField"`    // 默认 name
+	AvatarField  string `mapstructure:"avatarField"`  // 默认 picture
+}
+
+type APIKeyConfig struct {
+	Prefix           string        `mapstructure:"prefix"`           // 密钥前缀, 便于识别和扫描泄露
+	MaxPerUser       int           `mapstructure:"maxPerUser"`       // 每个用户最多有效密钥数, 0 表示不限制
+	LastUsedInterval time.Duration `mapstructure:"lastUsedInterval"` // 最后使用时间的更新间隔, 避免每次请求都写库
+}
+
+// TenantConfig 租户识别, 优先级: 令牌中的 tid > 请求头 > 子域名
+type TenantConfig struct {
+	Header     string `mapstructure:"header"`     // 请求头名称, 为空时不从请求头读取
+	BaseDomain string `mapstructure:"baseDomain"` // 主域名 (如 example.com), 为空时不从子域名读取
+}
+
+// LoginConfig 登录历史和可疑登录检测
+type LoginConfig struct {
+	IPv4PrefixLen int           `mapstructure:"ipv4PrefixLen"` // 判断新 IP 段时 IPv4 的前缀长度
+	IPv6PrefixLen int           `mapstructure:"ipv6PrefixLen"` // 判断新 IP 段时 IPv6 的前缀长度
+	StepUp        bool          `mapstructure:"stepUp"`        // 新设备或新 IP 段使用密码或第三方登录时, 需要输入发送到邮箱或手机的验证码
+	StepUpExpire  time.Duration `mapstructure:"stepUpExpire"`  // 登录二次验证有效期
+}
+
+type NotifierConfig struct {
+	Driver string `mapstructure:"driver"` // 通知驱动: log, sender
+}
+
+// PrivacyConfig 个人数据导出和账号注销
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration `mapstructure:"deletionGracePeriod"` // 注销冷静期, 期间可以撤销
+	EraseMode           string        `mapstructure:"eraseMode"`           // 冷静期结束后的处理方式: anonymize (匿名化保留账号行), delete (彻底删除)
+}
+
+// TrashConfig 软删除数据的保留和定时清理
+type TrashConfig struct {
+	PurgeCron  string                   `mapstructure:"purgeCron"`  // 清理任务的执行时间 (cron 表达式), 为空时不清理
+	Retention  time.Duration            `mapstructure:"retention"`  // 默认保留时间, 超过后彻底删除
+	Retentions map[string]time.Duration `mapstructure:"retentions"` // 按表名单独设置保留时间, 0 表示不清理
+}
+
+// RetentionOf 表的保留时间
+func (t TrashConfig) RetentionOf(table string) time.Duration {
+	if retention, ok := t.Retentions[table]; ok {
+		return retention
+	}
+	return t.Retention
+}
@@ -12,9 +12,51 @@ const (
 	REDIS_KEY_OF_RATE_LIMIT    = "api:rate_limit:%s:%s" // redis key of rate limit: zone:ip
 	REDIS_KEY_OF_AUTH_ROLES    = "auth:roles:%s"        // redis key of auth roles: uniCode
 
+	REDIS_KEY_OF_AUTH_PERMISSIONS    = "auth:permissions:%s" // redis key of auth permissions: uniCode
+	REDIS_EXPIRE_OF_AUTH_PERMISSIONS = 5 * time.Minute       // redis expire of auth permissions
+
+	REDIS_KEY_OF_AUTH_EFFECTIVE_ROLES = "auth:effective_roles:%s" // redis key of roles expanded by inheritance: uniCode
+
+	REDIS_KEY_OF_REFRESH_TOKEN        = "auth:refresh_token:%s"        // redis key of refresh token: sha256(token)
+	REDIS_KEY_OF_TOKEN_FAMILY_REVOKED = "auth:token_family_revoked:%s" // redis key of revoked token family: familyID
+	REDIS_KEY_OF_TOKEN_DENYLIST       = "auth:token_denylist:%s"       // redis key of revoked access token: jti
+	REDIS_KEY_OF_TOKEN_VALID_AFTER    = "auth:token_valid_after:%s"    // redis key of tokens valid after (unix ms): uniCode
+
+	REDIS_KEY_OF_MFA_CHALLENGE  = "auth:mfa_challenge:%s" // redis key of two-step login challenge: token
+	REDIS_KEY_OF_MFA_ENABLED    = "auth:mfa_enabled:%s"   // redis key of cached mfa enabled flag: uniCode
+	REDIS_EXPIRE_OF_MFA_ENABLED = 5 * time.Minute         // redis expire of cached mfa enabled flag
+
+	REDIS_KEY_OF_OAUTH_STATE = "auth:oauth_state:%s" // redis key of oauth authorization request: state
+
+	REDIS_KEY_OF_REAUTH    = "auth:reauth:%s" // redis key of re-authentication token: token
+	REDIS_EXPIRE_OF_REAUTH = 5 * time.Minute  // redis expire of re-authentication token
+
+	REDIS_KEY_OF_USER_BLOCKED    = "auth:user_blocked:%s" // redis key of non-active account status: uniCode
+	REDIS_EXPIRE_OF_USER_BLOCKED = 5 * time.Minute        // redis expire of account status loaded from database
+
+	REDIS_KEY_OF_LOGIN_STEP_UP = "auth:login_step_up:%s" // redis key of login step-up challenge: token
+
+	TASK_TYPE_OF_AUDIT_LOG     = "audit:log"     // asynq task type of writing audit log
+	TASK_TYPE_OF_LOGIN_NOTIFY  = "notify:login"  // asynq task type of new login notification
+	TASK_TYPE_OF_ACCOUNT_ERASE = "account:erase" // asynq task type of erasing a deleted account
+	TASK_TYPE_OF_TRASH_PURGE   = "trash:purge"   // asynq task type of purging soft-deleted rows
+
+	REDIS_KEY_OF_VERIFY_CODE              = "verify_code:%s:%s:%s"           // redis key of verify code: scene:channel:target
+	REDIS_KEY_OF_VERIFY_CODE_INTERVAL     = "verify_code:interval:%s:%s"     // redis key of verify code resend interval: channel:target
+	REDIS_KEY_OF_VERIFY_CODE_TARGET_COUNT = "verify_code:count:target:%s:%s" // redis key of verify code send count: channel:target
+	REDIS_KEY_OF_VERIFY_CODE_IP_COUNT     = "verify_code:count:ip:%s"        // redis key of verify code send count: ip
+	REDIS_EXPIRE_OF_VERIFY_CODE_TARGET    = 24 * time.Hour                   // redis expire of verify code send count per target
+	REDIS_EXPIRE_OF_VERIFY_CODE_IP        = 1 * time.Hour                    // redis expire of verify code send count per ip
+
 	CTX_KEY_OF_LOCALE        = "ctx:locale"
 	CTX_KEY_OF_TRANSLATOR    = "ctx:translator"
 	CTX_KEY_OF_USER_UNI_CODE = "ctx:user_uni_code"
+	CTX_KEY_OF_TOKEN_CLAIMS  = "ctx:token_claims"
+	CTX_KEY_OF_API_KEY       = "ctx:api_key"
+	CTX_KEY_OF_IMPERSONATOR  = "ctx:impersonator"
+	CTX_KEY_OF_TENANT_MEMBER = "ctx:tenant_member"
+	CTX_KEY_OF_REQUEST_ID    = "ctx:request_id"
+	CTX_KEY_OF_AUDITED       = "ctx:audited"
 )
 
 func RedisKeyOfRateLimit(zone string, ip string) string {
@@ -24,3 +66,67 @@ func RedisKeyOfRateLimit(zone string, ip string) string {
 func RedisKeyOfAuthRoles(uniCode string) string {
 	return fmt.Sprintf(REDIS_KEY_OF_AUTH_ROLES, uniCode)
 }
+
+func RedisKeyOfAuthEffectiveRoles(uniCode string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_AUTH_EFFECTIVE_ROLES, uniCode)
+}
+
+func RedisKeyOfAuthPermissions(uniCode string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_AUTH_PERMISSIONS, uniCode)
+}
+
+func RedisKeyOfVerifyCode(scene string, channel string, target string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_VERIFY_CODE, scene, channel, target)
+}
+
+func RedisKeyOfVerifyCodeInterval(channel string, target string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_VERIFY_CODE_INTERVAL, channel, target)
+}
+
+func RedisKeyOfVerifyCodeTargetCount(channel string, target string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_VERIFY_CODE_TARGET_COUNT, channel, target)
+}
+
+func RedisKeyOfVerifyCodeIPCount(ip string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_VERIFY_CODE_IP_COUNT, ip)
+}
+
+func RedisKeyOfRefreshToken(tokenHash string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_REFRESH_TOKEN, tokenHash)
+}
+
+func RedisKeyOfTokenFamilyRevoked(familyID string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_TOKEN_FAMILY_REVOKED, familyID)
+}
+
+func RedisKeyOfTokenDenylist(tokenID string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_TOKEN_DENYLIST, tokenID)
+}
+
+func RedisKeyOfTokenValidAfter(uniCode string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_TOKEN_VALID_AFTER, uniCode)
+}
+
+func RedisKeyOfMFAChallenge(token string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_MFA_CHALLENGE, token)
+}
+
+func RedisKeyOfMFAEnabled(uniCode string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_MFA_ENABLED, uniCode)
+}
+
+func RedisKeyOfOAuthState(state string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_OAUTH_STATE, state)
+}
+
+func RedisKeyOfReauth(token string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_REAUTH, token)
+}
+
+func RedisKeyOfUserBlocked(uniCode string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_USER_BLOCKED, uniCode)
+}
+
+func RedisKeyOfLoginStepUp(token string) string {
+	return fmt.Sprintf(REDIS_KEY_OF_LOGIN_STEP_UP, token)
+}
@@ -11,6 +11,8 @@ import (
 	"go-server-starter/pkg/utils"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -63,6 +65,10 @@ func (c *Context) ShouldBind(obj any) *exception.Exception {
 	if len(errs) > 0 {
 		return exception.InvalidParam.Append(errs...)
 	}
+	// filter[字段][操作] 这类嵌套参数无法绑定到结构体, 交给表格查询自己解析
+	if setter, ok := obj.(interface{ SetQueryValues(url.Values) }); ok {
+		setter.SetQueryValues(c.Gtx.Request.URL.Query())
+	}
 	return nil
 }
 
@@ -171,3 +177,32 @@ func (c *Context) GetUserAgent() string {
 	userAgent := c.Gtx.GetHeader("User-Agent")
 	return userAgent
 }
+
+// 获取客户端IP
+func (c *Context) GetClientIP() string {
+	return c.Gtx.ClientIP()
+}
+
+// 获取请求ID
+func (c *Context) GetRequestID() string {
+	return c.Gtx.GetString(constant.CTX_KEY_OF_REQUEST_ID)
+}
+
+// GetIfMatchVersion 从 If-Match 请求头读取版本号, 没有或为 * 时返回 nil
+func (c *Context) GetIfMatchVersion() (*uint64, *exception.Exception) {
+	value := strings.TrimSpace(c.Gtx.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, exception.InvalidParam.Append("If-Match must be a version number")
+	}
+	return &version, nil
+}
+
+// SetETag 将版本号写入 ETag 响应头, 客户端更新时通过 If-Match 带回
+func (c *Context) SetETag(version uint64) {
+	c.Gtx.Header("ETag", strconv.Quote(strconv.FormatUint(version, 10)))
+}
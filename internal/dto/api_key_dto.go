@@ -0,0 +1,38 @@
+package dto
+
+// 创建 API 密钥, scopes 为空时默认为 ["*"], expiresAt 为空表示永不过期
+type APIKeyCreateReqDto struct {
+	Name      string   `json:"name" binding:"required,max=64"`
+	Scopes    []string `json:"scopes" binding:"omitempty,dive,required,max=64"`
+	ExpiresAt string   `json:"expiresAt" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// 管理员为用户创建密钥时可指定为服务密钥
+type APIKeyAdminCreateReqDto struct {
+	APIKeyCreateReqDto
+	Type string `json:"type" binding:"omitempty,oneof=personal service"`
+}
+
+type APIKeyUpdateReqDto struct {
+	Name   *string  `json:"name" binding:"omitempty,max=64"`
+	Scopes []string `json:"scopes" binding:"omitempty,dive,required,max=64"`
+}
+
+type APIKeyResDto struct {
+	ID         uint64   `json:"id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	KeyID      string   `json:"keyId"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  string   `json:"createdAt"`
+	ExpiresAt  string   `json:"expiresAt"`
+	LastUsedAt string   `json:"lastUsedAt"`
+	LastUsedIP string   `json:"lastUsedIp"`
+	RevokedAt  string   `json:"revokedAt"`
+}
+
+// 完整密钥只在创建时返回一次
+type APIKeyCreateResDto struct {
+	APIKeyResDto
+	Key string `json:"key"`
+}
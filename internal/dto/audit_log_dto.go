@@ -0,0 +1,31 @@
+package dto
+
+// 审计日志查询, From/To 为 RFC3339 时间
+type AuditLogTableQueryReqDto struct {
+	PaginationReqDto
+	ActorUniCode *string `json:"actorUniCode" form:"actorUniCode"`
+	Impersonator *string `json:"impersonator" form:"impersonator"`
+	Action       *string `json:"action" form:"action"`
+	TargetType   *string `json:"targetType" form:"targetType"`
+	TargetID     *string `json:"targetId" form:"targetId"`
+	RequestID    *string `json:"requestId" form:"requestId"`
+	From         string  `json:"from" form:"from" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To           string  `json:"to" form:"to" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type AuditLogResDto struct {
+	ID           uint64         `json:"id"`
+	CreatedAt    string         `json:"createdAt"`
+	ActorUniCode string         `json:"actorUniCode"`
+	Impersonator string         `json:"impersonator"`
+	Action       string         `json:"action"`
+	TargetType   string         `json:"targetType"`
+	TargetID     string         `json:"targetId"`
+	Before       map[string]any `json:"before"`
+	After        map[string]any `json:"after"`
+	IP           string         `json:"ip"`
+	DeviceType   string         `json:"deviceType"`
+	DeviceID     string         `json:"deviceId"`
+	UserAgent    string         `json:"userAgent"`
+	RequestID    string         `json:"requestId"`
+}
@@ -1,5 +1,20 @@
 package dto
 
+import "go-server-starter/internal/enum"
+
+// 发送手机验证码
+type AuthSendMobileCodeReqDto struct {
+	Mobile      string               `json:"mobile" binding:"required"`
+	CountryCode string               `json:"countryCode" binding:"required"`
+	Scene       enum.VerifyCodeScene `json:"scene" binding:"omitempty,oneof=login"`
+}
+
+// 发送邮箱验证码
+type AuthSendEmailCodeReqDto struct {
+	Email string               `json:"email" binding:"required,email"`
+	Scene enum.VerifyCodeScene `json:"scene" binding:"omitempty,oneof=login"`
+}
+
 // 手机和手机验证码登录(没有注册自动注册)
 type AuthLoginByMobileAndCodeReqDto struct {
 	Mobile      string `json:"mobile" binding:"required"`
@@ -13,6 +28,80 @@ type AuthLoginByEmailAndCodeReqDto struct {
 	Code  string `json:"code" binding:"required"`
 }
 
+// 用户名和密码注册
+type AuthRegisterReqDto struct {
+	Username string `json:"username" binding:"required,username"`
+	Password string `json:"password" binding:"required,min=8,max=48"`
+	Nickname string `json:"nickname" binding:"omitempty,min=2,max=20"`
+}
+
+// 邮箱和密码登录
+type AuthLoginByEmailAndPasswordReqDto struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// 手机和密码登录
+type AuthLoginByMobileAndPasswordReqDto struct {
+	Mobile      string `json:"mobile" binding:"required"`
+	CountryCode string `json:"countryCode" binding:"required"`
+	Password    string `json:"password" binding:"required"`
+}
+
+// 用户名和密码登录
+type AuthLoginByUsernameAndPasswordReqDto struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// 修改密码
+type AuthChangePasswordReqDto struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8,max=48"`
+}
+
+// 设置初始密码 (验证码登录注册的用户)
+type AuthSetPasswordReqDto struct {
+	Password string `json:"password" binding:"required,min=8,max=48"`
+}
+
+// 刷新令牌
+type AuthRefreshTokenReqDto struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// 第三方登录回调, code 和 state 来自提供方的回调参数
+type AuthOAuthCallbackReqDto struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+type AuthOAuthAuthorizeResDto struct {
+	AuthorizeURL string `json:"authorizeUrl"`
+	State        string `json:"state"`
+}
+
+// 两步登录, 使用第一步返回的挑战令牌和两步验证码完成登录
+type AuthVerifyMFAReqDto struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// 登录二次验证, 使用挑战令牌和发送到邮箱或手机的验证码完成登录
+type AuthVerifyStepUpReqDto struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// 开启两步验证的用户, 第一步登录只返回 MFARequired 和挑战令牌, 需要二次验证时返回 StepUpRequired 和挑战令牌
 type AuthTokenResDto struct {
-	Token string `json:"token"`
+	AccessToken           string `json:"accessToken,omitempty"`
+	AccessTokenExpiresAt  string `json:"accessTokenExpiresAt,omitempty"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	RefreshTokenExpiresAt string `json:"refreshTokenExpiresAt,omitempty"`
+	MFARequired           bool   `json:"mfaRequired"`
+	StepUpRequired        bool   `json:"stepUpRequired"`
+	StepUpChannel         string `json:"stepUpChannel,omitempty"`
+	ChallengeToken        string `json:"challengeToken,omitempty"`
+	ChallengeExpiresAt    string `json:"challengeExpiresAt,omitempty"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+// 模拟登录只签发访问令牌, 过期后需要重新发起
+type ImpersonationResDto struct {
+	AccessToken          string `json:"accessToken"`
+	AccessTokenExpiresAt string `json:"accessTokenExpiresAt"`
+	UniCode              string `json:"uniCode"`
+}
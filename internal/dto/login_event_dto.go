@@ -0,0 +1,15 @@
+package dto
+
+type LoginEventResDto struct {
+	ID         uint64 `json:"id"`
+	CreatedAt  string `json:"createdAt"`
+	Method     string `json:"method"`
+	Outcome    string `json:"outcome"`
+	Reason     string `json:"reason"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"userAgent"`
+	DeviceType string `json:"deviceType"`
+	DeviceID   string `json:"deviceId"`
+	NewDevice  bool   `json:"newDevice"`
+	NewIPRange bool   `json:"newIpRange"`
+}
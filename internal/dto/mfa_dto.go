@@ -0,0 +1,22 @@
+package dto
+
+// 两步验证码, TOTP 验证码或恢复码
+type MFACodeReqDto struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAStatusResDto struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+}
+
+type MFATOTPEnrollResDto struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// 链接, 可生成二维码
+}
+
+// 恢复码只在生成时返回一次
+type MFARecoveryCodesResDto struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// 创建组织, Slug 用于请求头和子域名
+type OrganizationCreateReqDto struct {
+	Name string `json:"name" binding:"required,max=64"`
+	Slug string `json:"slug" binding:"required,min=2,max=32,alphanum,lowercase"`
+}
+
+type OrganizationResDto struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Role string `json:"role"` // 当前用户在组织中的角色
+}
+
+// 绑定组织的访问令牌, 没有刷新令牌, 过期后重新签发
+type OrganizationTokenResDto struct {
+	AccessToken          string `json:"accessToken"`
+	AccessTokenExpiresAt string `json:"accessTokenExpiresAt"`
+	Slug                 string `json:"slug"`
+}
+
+type OrganizationMemberResDto struct {
+	UserID   uint64 `json:"userId"`
+	UniCode  string `json:"uniCode"`
+	Nickname string `json:"nickname"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joinedAt"`
+}
+
+type OrganizationAddMemberReqDto struct {
+	UserID uint64 `json:"userId" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+type OrganizationUpdateMemberReqDto struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member"`
+}
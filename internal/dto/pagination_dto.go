@@ -1,5 +1,7 @@
 package dto
 
+import "net/url"
+
 type PaginationReqDto struct {
 	Page     int `json:"page" form:"page"`
 	PageSize int `json:"pageSize" form:"pageSize"`
@@ -12,3 +14,29 @@ type PaginationResDto[T any] struct {
 	TotalPage int64 `json:"totalPage"`
 	HasNext   bool  `json:"hasNext"`
 }
+
+// 游标分页, cursor 为上一页返回的 nextCursor 或 prevCursor, 为空时从第一页开始
+type CursorPaginationReqDto struct {
+	Cursor   string `json:"cursor" form:"cursor"`
+	PageSize int    `json:"pageSize" form:"pageSize"`
+}
+
+type CursorPaginationResDto[T any] struct {
+	Data       T      `json:"data"`
+	PageSize   int    `json:"pageSize"`
+	NextCursor string `json:"nextCursor"`
+	PrevCursor string `json:"prevCursor"`
+	HasNext    bool   `json:"hasNext"`
+	HasPrev    bool   `json:"hasPrev"`
+}
+
+// 表格查询, 支持 filter[字段][操作]=值, sort=-createdAt,nickname 和 q=关键字, 可用字段由 DTO 的 query 标签声明
+type TableQueryReqDto struct {
+	Sort   string     `json:"sort" form:"sort"`
+	Q      string     `json:"q" form:"q"`
+	Values url.Values `json:"-" form:"-"` // 原始查询参数, 由 ctx.ShouldBind 填充
+}
+
+func (d *TableQueryReqDto) SetQueryValues(values url.Values) {
+	d.Values = values
+}
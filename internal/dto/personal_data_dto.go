@@ -0,0 +1,23 @@
+package dto
+
+// 导出个人数据, zip 时每个模块一个 JSON 文件
+type PersonalDataExportReqDto struct {
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+type PersonalDataExportResDto struct {
+	UniCode    string         `json:"uniCode"`
+	ExportedAt string         `json:"exportedAt"`
+	Data       map[string]any `json:"data"` // 按模块名分组
+}
+
+// 申请注销账号, 需要先验证身份
+type AccountDeletionReqDto struct {
+	ReauthToken string `json:"reauthToken" binding:"required"`
+}
+
+type AccountDeletionResDto struct {
+	Requested   bool   `json:"requested"`
+	RequestedAt string `json:"requestedAt"`
+	ScheduledAt string `json:"scheduledAt"` // 冷静期结束时间, 之后账号数据会被清除
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// 回收站中的用户, 支持表格查询参数, 如 filter[deletedAt][gte]=2024-01-01&sort=-deletedAt
+type UserTrashTableQueryReqDto struct {
+	PaginationReqDto
+	TableQueryReqDto
+	DeletedAt *time.Time `json:"-" form:"-" query:"deletedAt,column=deleted_at,filter=between|gte|lte,sort"`
+	Nickname  *string    `json:"nickname" form:"nickname" query:"nickname,filter=contains|eq|prefix,sort,search"`
+	Email     *string    `json:"email" form:"email" query:"email,filter=prefix|eq,sort,search"`
+	Mobile    *string    `json:"mobile" form:"mobile" query:"mobile,filter=prefix|eq,search"`
+}
+
+type UserTrashItemResDto struct {
+	UserListItemResDto
+	DeletedAt string `json:"deletedAt"`
+}
+
+// 批量恢复用户, 任一用户无法恢复时全部不恢复
+type UserRestoreReqDto struct {
+	IDs []uint64 `json:"ids" binding:"required,min=1,max=100"`
+}
@@ -1,29 +1,44 @@
 package dto
 
+import (
+	"go-server-starter/internal/enum"
+	"time"
+)
+
 // 更新用户信息请求DTO
 type UserUpdateInfoReqDto struct {
 	Nickname  *string `json:"nickname" form:"nickname" binding:"required,min=2,max=20"`
 	AvatarURL *string `json:"avatarURL" form:"avatarURL" binding:"omitempty,url"`
 	Desc      *string `json:"desc" form:"desc" binding:"omitempty,max=200"`
+	Version   *uint64 `json:"version" form:"version"` // 期望的版本号, 必填, If-Match 请求头优先
 }
 
 type UserInfoResDto struct {
 	UniCode     string   `json:"uniCode"`
+	Username    string   `json:"username"`
 	Email       string   `json:"email"`
 	Mobile      string   `json:"mobile"`
 	CountryCode string   `json:"countryCode"`
 	Desc        string   `json:"desc"`
 	Nickname    string   `json:"nickname"`
 	AvatarURL   string   `json:"avatarURL"`
+	HasPassword bool     `json:"hasPassword"`
 	Roles       []string `json:"roles"`
+	Version     uint64   `json:"version"`
 }
 
+// 用户列表, 带 cursor 参数 (可以为空) 时使用游标分页, 否则按页码分页
 type UserTableQueryReqDto struct {
 	PaginationReqDto
-	Nickname    *string `json:"nickname" form:"nickname"`
-	Email       *string `json:"email" form:"email"`
-	Mobile      *string `json:"mobile" form:"mobile"`
-	CountryCode *string `json:"countryCode" form:"countryCode"`
+	TableQueryReqDto
+	Cursor      *string    `json:"cursor" form:"cursor"`
+	ID          *uint64    `json:"-" form:"-" query:"id,filter=eq|in,sort"`
+	CreatedAt   *time.Time `json:"-" form:"-" query:"createdAt,column=created_at,filter=between|gte|lte|gt|lt,sort"`
+	Status      *string    `json:"status" form:"status" query:"status,filter=eq|ne|in"`
+	Nickname    *string    `json:"nickname" form:"nickname" query:"nickname,filter=contains|eq|prefix,sort,search"`
+	Email       *string    `json:"email" form:"email" query:"email,filter=prefix|eq|suffix,sort,search"`
+	Mobile      *string    `json:"mobile" form:"mobile" query:"mobile,filter=prefix|eq,search"`
+	CountryCode *string    `json:"countryCode" form:"countryCode" query:"countryCode,column=country_code,filter=prefix|eq"`
 }
 
 type UserListItemResDto struct {
@@ -38,3 +53,51 @@ type UserListItemResDto struct {
 	AvatarURL   string   `json:"avatarURL"`
 	Roles       []string `json:"roles"`
 }
+
+// 向当前绑定的邮箱或手机发送身份验证码
+type UserSendReauthCodeReqDto struct {
+	Channel enum.VerifyCodeChannel `json:"channel" binding:"required,oneof=mobile email"`
+}
+
+// 身份验证, 使用密码或发送到当前邮箱/手机的验证码
+type UserReauthReqDto struct {
+	Password string                 `json:"password" binding:"required_without=Code"`
+	Channel  enum.VerifyCodeChannel `json:"channel" binding:"required_with=Code,omitempty,oneof=mobile email"`
+	Code     string                 `json:"code" binding:"required_without=Password"`
+}
+
+type UserReauthResDto struct {
+	ReauthToken string `json:"reauthToken"`
+	ExpiresAt   string `json:"expiresAt"`
+}
+
+// 向新邮箱发送绑定验证码
+type UserSendEmailBindCodeReqDto struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// 绑定或更换邮箱, 已绑定邮箱时需要 reauthToken
+type UserBindEmailReqDto struct {
+	Email       string `json:"email" binding:"required,email"`
+	Code        string `json:"code" binding:"required"`
+	ReauthToken string `json:"reauthToken"`
+}
+
+// 向新手机发送绑定验证码
+type UserSendMobileBindCodeReqDto struct {
+	Mobile      string `json:"mobile" binding:"required"`
+	CountryCode string `json:"countryCode" binding:"required"`
+}
+
+// 绑定或更换手机, 已绑定手机时需要 reauthToken
+type UserBindMobileReqDto struct {
+	Mobile      string `json:"mobile" binding:"required"`
+	CountryCode string `json:"countryCode" binding:"required"`
+	Code        string `json:"code" binding:"required"`
+	ReauthToken string `json:"reauthToken"`
+}
+
+// 解绑邮箱或手机
+type UserUnbindReqDto struct {
+	ReauthToken string `json:"reauthToken" binding:"required"`
+}
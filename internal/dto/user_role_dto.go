@@ -1 +1,18 @@
 package dto
+
+type UserRoleResDto struct {
+	ID          uint64   `json:"id"`
+	Code        string   `json:"code"`
+	Enabled     bool     `json:"enabled"`
+	Permissions []string `json:"permissions"`
+}
+
+// 启用或禁用角色
+type UserRoleSetEnabledReqDto struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// 为用户分配角色
+type UserRoleAssignReqDto struct {
+	RoleID uint64 `json:"roleId" binding:"required"`
+}
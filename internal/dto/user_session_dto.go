@@ -0,0 +1,15 @@
+package dto
+
+type UserSessionResDto struct {
+	ID         uint64 `json:"id"`
+	DeviceType string `json:"deviceType"`
+	DeviceID   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	DeviceInfo string `json:"deviceInfo"`
+	UserAgent  string `json:"userAgent"`
+	IP         string `json:"ip"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	IsCurrent  bool   `json:"isCurrent"`
+}
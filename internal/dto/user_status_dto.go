@@ -0,0 +1,16 @@
+package dto
+
+// 修改账号状态, 暂停时需要指定截止时间 (RFC3339)
+type UserSetStatusReqDto struct {
+	Status string `json:"status" binding:"required,oneof=active suspended banned deactivated"`
+	Reason string `json:"reason" binding:"max=255"`
+	Until  string `json:"until" binding:"required_if=Status suspended"`
+}
+
+type UserStatusResDto struct {
+	Status string  `json:"status"`
+	Reason string  `json:"reason"`
+	Until  string  `json:"until"`
+	SetBy  *uint64 `json:"setBy"`
+	SetAt  string  `json:"setAt"`
+}
@@ -0,0 +1,4 @@
+package enum
+
+// ENUM(personal, service)
+type APIKeyType string
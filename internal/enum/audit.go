@@ -0,0 +1,4 @@
+package enum
+
+// ENUM(user, role)
+type AuditTargetType string
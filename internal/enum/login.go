@@ -0,0 +1,7 @@
+package enum
+
+// ENUM(email_code, mobile_code, email_password, mobile_password, username_password, register, oauth, mfa)
+type LoginMethod string
+
+// ENUM(success, failure, challenge)
+type LoginOutcome string
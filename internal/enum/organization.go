@@ -0,0 +1,4 @@
+package enum
+
+// ENUM(owner, admin, member)
+type OrganizationRole string
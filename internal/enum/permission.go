@@ -0,0 +1,4 @@
+package enum
+
+// ENUM(user_read="user:read", user_write="user:write", user_list="user:list", session_manage="session:manage", api_key_manage="api_key:manage", role_read="role:read", role_manage="role:manage", user_manage="user:manage", audit_read="audit:read", org_read="org:read", org_write="org:write")
+type PermissionCode string
@@ -0,0 +1,4 @@
+package enum
+
+// ENUM(active, suspended, banned, deactivated)
+type UserStatus string
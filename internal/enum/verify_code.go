@@ -0,0 +1,7 @@
+package enum
+
+// ENUM(login, bind, reauth, step_up)
+type VerifyCodeScene string
+
+// ENUM(mobile, email)
+type VerifyCodeChannel string
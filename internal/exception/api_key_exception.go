@@ -0,0 +1,15 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	APIKeyInvalid       = APIKey.New(http.StatusUnauthorized, "api key is invalid", i18n.ExcAPIKeyInvalid)
+	APIKeyExpired       = APIKey.New(http.StatusUnauthorized, "api key has expired", i18n.ExcAPIKeyExpired)
+	APIKeyRevoked       = APIKey.New(http.StatusUnauthorized, "api key has been revoked", i18n.ExcAPIKeyRevoked)
+	APIKeyNotFound      = APIKey.New(http.StatusNotFound, "api key not found", i18n.ExcAPIKeyNotFound)
+	APIKeyLimitExceeded = APIKey.New(http.StatusBadRequest, "api key limit exceeded", i18n.ExcAPIKeyLimitExceeded)
+	APIKeyScopeDenied   = APIKey.New(http.StatusForbidden, "api key scope not allowed", i18n.ExcAPIKeyScopeDenied)
+)
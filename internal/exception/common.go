@@ -28,4 +28,6 @@ var (
 	ServiceError         = Common.New(http.StatusServiceUnavailable, "service error", i18n.ExcServiceError)
 	ServiceTimeout       = Common.New(http.StatusServiceUnavailable, "service timeout", i18n.ExcServiceTimeout)
 	DatabaseError        = Common.New(http.StatusInternalServerError, "database error", i18n.ExcDatabaseError)
+	VersionConflict      = Common.New(http.StatusConflict, "the resource has been modified, please reload and try again", i18n.ExcVersionConflict)
+	PreconditionRequired = Common.New(http.StatusPreconditionRequired, "version is required, send it with If-Match or in the body", i18n.ExcPreconditionRequired)
 )
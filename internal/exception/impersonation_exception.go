@@ -0,0 +1,12 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	ImpersonationForbidden     = Impersonation.New(http.StatusForbidden, "not allowed while impersonating", i18n.ExcImpersonationForbidden)
+	ImpersonationNotActive     = Impersonation.New(http.StatusBadRequest, "the current token is not an impersonation token", i18n.ExcImpersonationNotActive)
+	ImpersonationTargetInvalid = Impersonation.New(http.StatusBadRequest, "this user cannot be impersonated", i18n.ExcImpersonationTargetInvalid)
+)
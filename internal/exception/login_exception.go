@@ -0,0 +1,10 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	LoginStepUpInvalid = Login.New(http.StatusUnauthorized, "the login verification is invalid or expired", i18n.ExcLoginStepUpInvalid)
+)
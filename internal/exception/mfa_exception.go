@@ -0,0 +1,16 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	MFANotEnrolled              = MFA.New(http.StatusBadRequest, "two-factor authentication has not been set up", i18n.ExcMFANotEnrolled)
+	MFAAlreadyEnabled           = MFA.New(http.StatusBadRequest, "two-factor authentication is already enabled", i18n.ExcMFAAlreadyEnabled)
+	MFANotEnabled               = MFA.New(http.StatusBadRequest, "two-factor authentication is not enabled", i18n.ExcMFANotEnabled)
+	MFACodeIsIncorrect          = MFA.New(http.StatusBadRequest, "the two-factor authentication code is incorrect", i18n.ExcMFACodeIsIncorrect)
+	MFAChallengeInvalid         = MFA.New(http.StatusUnauthorized, "the login challenge is invalid or expired", i18n.ExcMFAChallengeInvalid)
+	MFAChallengeTooManyAttempts = MFA.New(http.StatusTooManyRequests, "too many incorrect attempts, please login again", i18n.ExcMFAChallengeTooManyAttempts)
+	MFARequired                 = MFA.New(http.StatusForbidden, "two-factor authentication is required for your role", i18n.ExcMFARequired)
+)
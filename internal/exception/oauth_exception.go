@@ -0,0 +1,12 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	OAuthProviderNotFound = OAuth.New(http.StatusNotFound, "oauth provider not found", i18n.ExcOAuthProviderNotFound)
+	OAuthStateInvalid     = OAuth.New(http.StatusBadRequest, "oauth state is invalid or expired", i18n.ExcOAuthStateInvalid)
+	OAuthExchangeFailed   = OAuth.New(http.StatusBadGateway, "oauth login failed", i18n.ExcOAuthExchangeFailed)
+)
@@ -0,0 +1,18 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	OrganizationNotFound       = Organization.New(http.StatusNotFound, "organization not found", i18n.ExcOrganizationNotFound)
+	OrganizationSlugTaken      = Organization.New(http.StatusBadRequest, "organization slug is already taken", i18n.ExcOrganizationSlugTaken)
+	OrganizationNotMember      = Organization.New(http.StatusForbidden, "not a member of the organization", i18n.ExcOrganizationNotMember)
+	OrganizationRoleForbidden  = Organization.New(http.StatusForbidden, "organization role not allowed", i18n.ExcOrganizationRoleForbidden)
+	OrganizationMemberNotFound = Organization.New(http.StatusNotFound, "organization member not found", i18n.ExcOrganizationMemberNotFound)
+	OrganizationMemberExists   = Organization.New(http.StatusBadRequest, "user is already a member of the organization", i18n.ExcOrganizationMemberExists)
+	OrganizationLastOwner      = Organization.New(http.StatusBadRequest, "cannot remove the last owner of the organization", i18n.ExcOrganizationLastOwner)
+	OrganizationTenantRequired = Organization.New(http.StatusBadRequest, "organization is not specified", i18n.ExcOrganizationTenantRequired)
+	OrganizationTenantMismatch = Organization.New(http.StatusForbidden, "the token is bound to another organization", i18n.ExcOrganizationTenantMismatch)
+)
@@ -11,9 +11,17 @@ type ExceptionScope struct {
 }
 
 var (
-	Common   = &ExceptionScope{name: "common", baseCode: 1000}
-	User     = &ExceptionScope{name: "user", baseCode: 20000}
-	UserRole = &ExceptionScope{name: "user_role", baseCode: 21000}
+	Common        = &ExceptionScope{name: "common", baseCode: 1000}
+	User          = &ExceptionScope{name: "user", baseCode: 20000}
+	UserRole      = &ExceptionScope{name: "user_role", baseCode: 21000}
+	VerifyCode    = &ExceptionScope{name: "verify_code", baseCode: 22000}
+	UserSession   = &ExceptionScope{name: "user_session", baseCode: 23000}
+	MFA           = &ExceptionScope{name: "mfa", baseCode: 24000}
+	OAuth         = &ExceptionScope{name: "oauth", baseCode: 25000}
+	APIKey        = &ExceptionScope{name: "api_key", baseCode: 26000}
+	Impersonation = &ExceptionScope{name: "impersonation", baseCode: 27000}
+	Organization  = &ExceptionScope{name: "organization", baseCode: 28000}
+	Login         = &ExceptionScope{name: "login", baseCode: 29000}
 )
 
 // New creates a new exception with auto-incrementing code within the module
@@ -1,6 +1,7 @@
 package exception
 
 import (
+	"go-server-starter/internal/enum"
 	"go-server-starter/internal/i18n"
 	"net/http"
 )
@@ -16,4 +17,31 @@ var (
 	UserPasswordIsIncorrect               = User.New(http.StatusBadRequest, "the password is incorrect", i18n.ExcUserPasswordIsIncorrect)
 	UserNotFound                          = User.New(http.StatusNotFound, "user not found", i18n.ExcUserNotFound)
 	UserUpdateInfoFailed                  = User.New(http.StatusInternalServerError, "user update info failed", i18n.ExcUserUpdateInfoFailed)
+	UserUsernameHasBeenRegistered         = User.New(http.StatusBadRequest, "the username has already been registered", i18n.ExcUserUsernameHasBeenRegistered)
+	UserPasswordNotSet                    = User.New(http.StatusBadRequest, "the password has not been set", i18n.ExcUserPasswordNotSet)
+	UserPasswordHasBeenSet                = User.New(http.StatusBadRequest, "the password has already been set", i18n.ExcUserPasswordHasBeenSet)
+	UserReauthRequired                    = User.New(http.StatusForbidden, "re-authentication is required", i18n.ExcUserReauthRequired)
+	UserReauthTokenInvalid                = User.New(http.StatusForbidden, "re-authentication token is invalid or expired", i18n.ExcUserReauthTokenInvalid)
+	UserLastLoginIdentifier               = User.New(http.StatusBadRequest, "cannot remove the last login method", i18n.ExcUserLastLoginIdentifier)
+	UserSuspended                         = User.New(http.StatusForbidden, "the account has been suspended", i18n.ExcUserSuspended)
+	UserBanned                            = User.New(http.StatusForbidden, "the account has been banned", i18n.ExcUserBanned)
+	UserDeactivated                       = User.New(http.StatusForbidden, "the account has been deactivated", i18n.ExcUserDeactivated)
+	UserStatusChangeForbidden             = User.New(http.StatusForbidden, "no permission to change the status of this account", i18n.ExcUserStatusChangeForbidden)
+	UserDeletionNotRequested              = User.New(http.StatusBadRequest, "no account deletion has been requested", i18n.ExcUserDeletionNotRequested)
+	UserRestoreConflict                   = User.New(http.StatusConflict, "the username, email or mobile number is used by another user", i18n.ExcUserRestoreConflict)
+	UserNotInTrash                        = User.New(http.StatusNotFound, "user not found in trash", i18n.ExcUserNotInTrash)
 )
+
+// UserStatusException 非 active 账号对应的异常, active 返回 nil
+func UserStatusException(status enum.UserStatus) *Exception {
+	switch status {
+	case enum.UserStatusSuspended:
+		return UserSuspended
+	case enum.UserStatusBanned:
+		return UserBanned
+	case enum.UserStatusDeactivated:
+		return UserDeactivated
+	default:
+		return nil
+	}
+}
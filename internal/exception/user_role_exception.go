@@ -6,6 +6,9 @@ import (
 )
 
 var (
-	UserRoleNotFound      = UserRole.New(http.StatusNotFound, "user role not found", i18n.ExcUserRoleNotFound)
-	UserRoleAlreadyExists = UserRole.New(http.StatusBadRequest, "user role already exists", i18n.ExcUserRoleAlreadyExists)
+	UserRoleNotFound       = UserRole.New(http.StatusNotFound, "user role not found", i18n.ExcUserRoleNotFound)
+	UserRoleAlreadyExists  = UserRole.New(http.StatusBadRequest, "user role already exists", i18n.ExcUserRoleAlreadyExists)
+	UserRoleGrantForbidden = UserRole.New(http.StatusForbidden, "only super admin can grant or revoke admin roles", i18n.ExcUserRoleGrantForbidden)
+	UserRoleLastSuperAdmin = UserRole.New(http.StatusBadRequest, "cannot revoke the last super admin", i18n.ExcUserRoleLastSuperAdmin)
+	UserRoleCannotDisable  = UserRole.New(http.StatusBadRequest, "the super admin role cannot be disabled", i18n.ExcUserRoleCannotDisable)
 )
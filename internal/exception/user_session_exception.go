@@ -0,0 +1,10 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	UserSessionNotFound = UserSession.New(http.StatusNotFound, "user session not found", i18n.ExcUserSessionNotFound)
+)
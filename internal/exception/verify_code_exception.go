@@ -0,0 +1,15 @@
+package exception
+
+import (
+	"go-server-starter/internal/i18n"
+	"net/http"
+)
+
+var (
+	VerifyCodeNotFoundOrExpired = VerifyCode.New(http.StatusBadRequest, "the verification code does not exist or has expired", i18n.ExcVerifyCodeNotFoundOrExpired)
+	VerifyCodeUsed              = VerifyCode.New(http.StatusBadRequest, "the verification code has already been used", i18n.ExcVerifyCodeUsed)
+	VerifyCodeTooManyAttempts   = VerifyCode.New(http.StatusBadRequest, "too many incorrect attempts, please request a new verification code", i18n.ExcVerifyCodeTooManyAttempts)
+	VerifyCodeSendTooFrequently = VerifyCode.New(http.StatusTooManyRequests, "verification code sent too frequently", i18n.ExcVerifyCodeSendTooFrequently)
+	VerifyCodeSendLimitExceeded = VerifyCode.New(http.StatusTooManyRequests, "verification code send limit exceeded", i18n.ExcVerifyCodeSendLimitExceeded)
+	VerifyCodeSendFailed        = VerifyCode.New(http.StatusInternalServerError, "verification code send failed", i18n.ExcVerifyCodeSendFailed)
+)
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler interface {
+	GetMyKeys(c *gin.Context)
+	CreateMyKey(c *gin.Context)
+	UpdateMyKey(c *gin.Context)
+	RevokeMyKey(c *gin.Context)
+	GetUserKeys(c *gin.Context)
+	CreateUserKey(c *gin.Context)
+	UpdateUserKey(c *gin.Context)
+	RevokeUserKey(c *gin.Context)
+}
+
+type APIKeyHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewAPIKeyHandler(logger *zap.Logger, service service.Service) APIKeyHandler {
+	return &APIKeyHandlerImpl{logger: logger, service: service}
+}
+
+func (h *APIKeyHandlerImpl) GetMyKeys(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.APIKey().GetMyKeys(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *APIKeyHandlerImpl) CreateMyKey(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.APIKeyCreateReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.APIKey().CreateMyKey(ctx, &params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *APIKeyHandlerImpl) UpdateMyKey(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	keyID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.APIKeyUpdateReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.APIKey().UpdateMyKey(ctx, keyID, &params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *APIKeyHandlerImpl) RevokeMyKey(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	keyID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.APIKey().RevokeMyKey(ctx, keyID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *APIKeyHandlerImpl) GetUserKeys(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.APIKey().GetUserKeys(ctx, userID)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *APIKeyHandlerImpl) CreateUserKey(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.APIKeyAdminCreateReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.APIKey().CreateUserKey(ctx, userID, &params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *APIKeyHandlerImpl) UpdateUserKey(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	keyID, err := ctx.GetPathParamID("keyId")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.APIKeyUpdateReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.APIKey().UpdateUserKey(ctx, userID, keyID, &params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *APIKeyHandlerImpl) RevokeUserKey(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	keyID, err := ctx.GetPathParamID("keyId")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.APIKey().RevokeUserKey(ctx, userID, keyID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
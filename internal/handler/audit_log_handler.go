@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuditLogHandler interface {
+	GetTable(c *gin.Context)
+}
+
+type AuditLogHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewAuditLogHandler(logger *zap.Logger, service service.Service) AuditLogHandler {
+	return &AuditLogHandlerImpl{logger: logger, service: service}
+}
+
+func (h *AuditLogHandlerImpl) GetTable(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuditLogTableQueryReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Audit().GetTable(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
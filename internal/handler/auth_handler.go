@@ -10,8 +10,24 @@ import (
 )
 
 type AuthHandler interface {
+	SendMobileCode(c *gin.Context)
+	SendEmailCode(c *gin.Context)
 	LoginByMobileAndCode(c *gin.Context)
 	LoginByEmailAndCode(c *gin.Context)
+	Register(c *gin.Context)
+	LoginByEmailAndPassword(c *gin.Context)
+	LoginByMobileAndPassword(c *gin.Context)
+	LoginByUsernameAndPassword(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	SetPassword(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	VerifyMFA(c *gin.Context)
+	VerifyStepUp(c *gin.Context)
+	GetOAuthProviders(c *gin.Context)
+	OAuthAuthorize(c *gin.Context)
+	OAuthCallback(c *gin.Context)
 }
 
 type AuthHandlerImpl struct {
@@ -52,3 +68,212 @@ func (h *AuthHandlerImpl) LoginByEmailAndCode(c *gin.Context) {
 	}
 	ctx.ToSuccess(res)
 }
+
+func (h *AuthHandlerImpl) SendMobileCode(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthSendMobileCodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Auth().SendMobileCode(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *AuthHandlerImpl) SendEmailCode(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthSendEmailCodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Auth().SendEmailCode(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *AuthHandlerImpl) Register(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthRegisterReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().Register(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) LoginByEmailAndPassword(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthLoginByEmailAndPasswordReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().LoginByEmailAndPassword(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) LoginByMobileAndPassword(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthLoginByMobileAndPasswordReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().LoginByMobileAndPassword(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) LoginByUsernameAndPassword(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthLoginByUsernameAndPasswordReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().LoginByUsernameAndPassword(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) ChangePassword(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthChangePasswordReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Auth().ChangePassword(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *AuthHandlerImpl) SetPassword(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthSetPasswordReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Auth().SetPassword(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *AuthHandlerImpl) RefreshToken(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthRefreshTokenReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().RefreshToken(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) Logout(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	if err := h.service.Auth().Logout(ctx); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *AuthHandlerImpl) LogoutAll(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	if err := h.service.Auth().LogoutAll(ctx); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *AuthHandlerImpl) VerifyMFA(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthVerifyMFAReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().VerifyMFA(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) VerifyStepUp(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthVerifyStepUpReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().VerifyStepUp(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) GetOAuthProviders(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	ctx.ToSuccess(h.service.Auth().GetOAuthProviders(ctx))
+}
+
+func (h *AuthHandlerImpl) OAuthAuthorize(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.Auth().OAuthAuthorize(ctx, c.Param("provider"))
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *AuthHandlerImpl) OAuthCallback(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AuthOAuthCallbackReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Auth().OAuthCallback(ctx, c.Param("provider"), params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
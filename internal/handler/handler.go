@@ -11,23 +11,56 @@ type Handler interface {
 	User() UserHandler
 	UserRole() UserRoleHandler
 	Auth() AuthHandler
+	UserSession() UserSessionHandler
+	MFA() MFAHandler
+	APIKey() APIKeyHandler
+	UserAccount() UserAccountHandler
+	UserStatus() UserStatusHandler
+	Impersonation() ImpersonationHandler
+	Organization() OrganizationHandler
+	AuditLog() AuditLogHandler
+	LoginEvent() LoginEventHandler
+	PersonalData() PersonalDataHandler
+	Trash() TrashHandler
 }
 
 type HandlerImpl struct {
-	logger          *zap.Logger
-	helloHandler    HelloHandler
-	userHandler     UserHandler
-	userRoleHandler UserRoleHandler
-	authHandler     AuthHandler
+	logger               *zap.Logger
+	helloHandler         HelloHandler
+	userHandler          UserHandler
+	userRoleHandler      UserRoleHandler
+	authHandler          AuthHandler
+	userSessionHandler   UserSessionHandler
+	mfaHandler           MFAHandler
+	apiKeyHandler        APIKeyHandler
+	userAccountHandler   UserAccountHandler
+	userStatusHandler    UserStatusHandler
+	impersonationHandler ImpersonationHandler
+	organizationHandler  OrganizationHandler
+	auditLogHandler      AuditLogHandler
+	loginEventHandler    LoginEventHandler
+	personalDataHandler  PersonalDataHandler
+	trashHandler         TrashHandler
 }
 
 func NewHandler(service service.Service, logger *zap.Logger) Handler {
 	return &HandlerImpl{
-		logger:          logger,
-		helloHandler:    NewHelloHandler(logger),
-		userHandler:     NewUserHandler(logger, service),
-		userRoleHandler: NewUserRoleHandler(logger, service),
-		authHandler:     NewAuthHandler(logger, service),
+		logger:               logger,
+		helloHandler:         NewHelloHandler(logger),
+		userHandler:          NewUserHandler(logger, service),
+		userRoleHandler:      NewUserRoleHandler(logger, service),
+		authHandler:          NewAuthHandler(logger, service),
+		userSessionHandler:   NewUserSessionHandler(logger, service),
+		mfaHandler:           NewMFAHandler(logger, service),
+		apiKeyHandler:        NewAPIKeyHandler(logger, service),
+		userAccountHandler:   NewUserAccountHandler(logger, service),
+		userStatusHandler:    NewUserStatusHandler(logger, service),
+		impersonationHandler: NewImpersonationHandler(logger, service),
+		organizationHandler:  NewOrganizationHandler(logger, service),
+		auditLogHandler:      NewAuditLogHandler(logger, service),
+		loginEventHandler:    NewLoginEventHandler(logger, service),
+		personalDataHandler:  NewPersonalDataHandler(logger, service),
+		trashHandler:         NewTrashHandler(logger, service),
 	}
 }
 
@@ -46,3 +79,47 @@ func (h *HandlerImpl) UserRole() UserRoleHandler {
 func (h *HandlerImpl) Auth() AuthHandler {
 	return h.authHandler
 }
+
+func (h *HandlerImpl) UserSession() UserSessionHandler {
+	return h.userSessionHandler
+}
+
+func (h *HandlerImpl) MFA() MFAHandler {
+	return h.mfaHandler
+}
+
+func (h *HandlerImpl) APIKey() APIKeyHandler {
+	return h.apiKeyHandler
+}
+
+func (h *HandlerImpl) UserAccount() UserAccountHandler {
+	return h.userAccountHandler
+}
+
+func (h *HandlerImpl) UserStatus() UserStatusHandler {
+	return h.userStatusHandler
+}
+
+func (h *HandlerImpl) Impersonation() ImpersonationHandler {
+	return h.impersonationHandler
+}
+
+func (h *HandlerImpl) Organization() OrganizationHandler {
+	return h.organizationHandler
+}
+
+func (h *HandlerImpl) AuditLog() AuditLogHandler {
+	return h.auditLogHandler
+}
+
+func (h *HandlerImpl) LoginEvent() LoginEventHandler {
+	return h.loginEventHandler
+}
+
+func (h *HandlerImpl) PersonalData() PersonalDataHandler {
+	return h.personalDataHandler
+}
+
+func (h *HandlerImpl) Trash() TrashHandler {
+	return h.trashHandler
+}
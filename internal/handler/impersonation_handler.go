@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ImpersonationHandler interface {
+	Start(c *gin.Context)
+	End(c *gin.Context)
+}
+
+type ImpersonationHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewImpersonationHandler(logger *zap.Logger, service service.Service) ImpersonationHandler {
+	return &ImpersonationHandlerImpl{logger: logger, service: service}
+}
+
+func (h *ImpersonationHandlerImpl) Start(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Impersonation().Start(ctx, userID)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *ImpersonationHandlerImpl) End(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	if err := h.service.Impersonation().End(ctx); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type LoginEventHandler interface {
+	GetMyHistory(c *gin.Context)
+}
+
+type LoginEventHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewLoginEventHandler(logger *zap.Logger, service service.Service) LoginEventHandler {
+	return &LoginEventHandlerImpl{logger: logger, service: service}
+}
+
+func (h *LoginEventHandlerImpl) GetMyHistory(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.PaginationReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.LoginEvent().GetMyHistory(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
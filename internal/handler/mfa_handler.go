@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type MFAHandler interface {
+	GetStatus(c *gin.Context)
+	EnrollTOTP(c *gin.Context)
+	ConfirmTOTP(c *gin.Context)
+	DisableTOTP(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+}
+
+type MFAHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewMFAHandler(logger *zap.Logger, service service.Service) MFAHandler {
+	return &MFAHandlerImpl{logger: logger, service: service}
+}
+
+func (h *MFAHandlerImpl) GetStatus(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.MFA().GetStatus(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *MFAHandlerImpl) EnrollTOTP(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.MFA().EnrollTOTP(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *MFAHandlerImpl) ConfirmTOTP(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.MFACodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.MFA().ConfirmTOTP(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *MFAHandlerImpl) DisableTOTP(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.MFACodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.MFA().DisableTOTP(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *MFAHandlerImpl) RegenerateRecoveryCodes(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.MFACodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.MFA().RegenerateRecoveryCodes(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type OrganizationHandler interface {
+	Create(c *gin.Context)
+	GetMine(c *gin.Context)
+	GetCurrent(c *gin.Context)
+	IssueToken(c *gin.Context)
+	GetMembers(c *gin.Context)
+	AddMember(c *gin.Context)
+	UpdateMember(c *gin.Context)
+	RemoveMember(c *gin.Context)
+}
+
+type OrganizationHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewOrganizationHandler(logger *zap.Logger, service service.Service) OrganizationHandler {
+	return &OrganizationHandlerImpl{logger: logger, service: service}
+}
+
+func (h *OrganizationHandlerImpl) Create(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.OrganizationCreateReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Organization().Create(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *OrganizationHandlerImpl) GetMine(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.Organization().GetMine(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *OrganizationHandlerImpl) GetCurrent(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.Organization().GetCurrent(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *OrganizationHandlerImpl) IssueToken(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.Organization().IssueToken(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *OrganizationHandlerImpl) GetMembers(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.Organization().GetMembers(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *OrganizationHandlerImpl) AddMember(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.OrganizationAddMemberReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Organization().AddMember(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *OrganizationHandlerImpl) UpdateMember(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.OrganizationUpdateMemberReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Organization().UpdateMember(ctx, userID, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *OrganizationHandlerImpl) RemoveMember(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Organization().RemoveMember(ctx, userID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"fmt"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type PersonalDataHandler interface {
+	Export(c *gin.Context)
+	GetDeletion(c *gin.Context)
+	RequestDeletion(c *gin.Context)
+	CancelDeletion(c *gin.Context)
+}
+
+type PersonalDataHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewPersonalDataHandler(logger *zap.Logger, service service.Service) PersonalDataHandler {
+	return &PersonalDataHandlerImpl{logger: logger, service: service}
+}
+
+// Export 默认返回 JSON, format=zip 时以附件下载
+func (h *PersonalDataHandlerImpl) Export(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.PersonalDataExportReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if params.Format != "zip" {
+		res, err := h.service.PersonalData().Export(ctx)
+		if err != nil {
+			ctx.ToError(err)
+			return
+		}
+		ctx.ToSuccess(res)
+		return
+	}
+	data, err := h.service.PersonalData().ExportArchive(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	filename := fmt.Sprintf("personal-data-%s.zip", time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+func (h *PersonalDataHandlerImpl) GetDeletion(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.PersonalData().GetDeletion(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *PersonalDataHandlerImpl) RequestDeletion(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.AccountDeletionReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.PersonalData().RequestDeletion(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *PersonalDataHandlerImpl) CancelDeletion(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	if err := h.service.PersonalData().CancelDeletion(ctx); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
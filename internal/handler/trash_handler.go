@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type TrashHandler interface {
+	DeleteUser(c *gin.Context)
+	GetTrashedUsers(c *gin.Context)
+	RestoreUser(c *gin.Context)
+	RestoreUsers(c *gin.Context)
+}
+
+type TrashHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewTrashHandler(logger *zap.Logger, service service.Service) TrashHandler {
+	return &TrashHandlerImpl{logger: logger, service: service}
+}
+
+func (h *TrashHandlerImpl) DeleteUser(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Trash().DeleteUser(ctx, userID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *TrashHandlerImpl) GetTrashedUsers(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserTrashTableQueryReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.Trash().GetTrashedUsers(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *TrashHandlerImpl) RestoreUser(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Trash().RestoreUsers(ctx, []uint64{userID}); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *TrashHandlerImpl) RestoreUsers(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserRestoreReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.Trash().RestoreUsers(ctx, params.IDs); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UserAccountHandler interface {
+	SendReauthCode(c *gin.Context)
+	Reauth(c *gin.Context)
+	SendEmailBindCode(c *gin.Context)
+	BindEmail(c *gin.Context)
+	UnbindEmail(c *gin.Context)
+	SendMobileBindCode(c *gin.Context)
+	BindMobile(c *gin.Context)
+	UnbindMobile(c *gin.Context)
+}
+
+type UserAccountHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewUserAccountHandler(logger *zap.Logger, service service.Service) UserAccountHandler {
+	return &UserAccountHandlerImpl{logger: logger, service: service}
+}
+
+func (h *UserAccountHandlerImpl) SendReauthCode(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserSendReauthCodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().SendReauthCode(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserAccountHandlerImpl) Reauth(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserReauthReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.UserAccount().Reauth(ctx, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *UserAccountHandlerImpl) SendEmailBindCode(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserSendEmailBindCodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().SendEmailBindCode(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserAccountHandlerImpl) BindEmail(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserBindEmailReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().BindEmail(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserAccountHandlerImpl) UnbindEmail(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserUnbindReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().UnbindEmail(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserAccountHandlerImpl) SendMobileBindCode(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserSendMobileBindCodeReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().SendMobileBindCode(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserAccountHandlerImpl) BindMobile(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserBindMobileReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().BindMobile(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserAccountHandlerImpl) UnbindMobile(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	var params dto.UserUnbindReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserAccount().UnbindMobile(ctx, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
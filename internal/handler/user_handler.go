@@ -36,6 +36,7 @@ func (h *UserHandlerImpl) GetInfo(c *gin.Context) {
 		ctx.ToError(err)
 		return
 	}
+	ctx.SetETag(user.Version)
 	ctx.ToSuccess(user)
 }
 
@@ -46,11 +47,20 @@ func (h *UserHandlerImpl) UpdateInfo(c *gin.Context) {
 		ctx.ToError(err)
 		return
 	}
+	version, err := ctx.GetIfMatchVersion()
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if version != nil {
+		params.Version = version
+	}
 	res, err := h.service.User().UpdateInfo(ctx, params)
 	if err != nil {
 		ctx.ToError(err)
 		return
 	}
+	ctx.SetETag(res.Version)
 	ctx.ToSuccess(res)
 }
 
@@ -1,12 +1,21 @@
 package handler
 
 import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
 	"go-server-starter/internal/service"
 
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type UserRoleHandler interface {
+	GetRoles(c *gin.Context)
+	SetRoleEnabled(c *gin.Context)
+	GetUserRoles(c *gin.Context)
+	GetUserEffectiveRoles(c *gin.Context)
+	AssignUserRole(c *gin.Context)
+	RevokeUserRole(c *gin.Context)
 }
 
 type UserRoleHandlerImpl struct {
@@ -17,3 +26,100 @@ type UserRoleHandlerImpl struct {
 func NewUserRoleHandler(logger *zap.Logger, service service.Service) UserRoleHandler {
 	return &UserRoleHandlerImpl{logger: logger, service: service}
 }
+
+func (h *UserRoleHandlerImpl) GetRoles(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.UserRole().GetRoles(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *UserRoleHandlerImpl) SetRoleEnabled(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	roleID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.UserRoleSetEnabledReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserRole().SetRoleEnabled(ctx, roleID, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserRoleHandlerImpl) GetUserRoles(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.UserRole().GetUserRoles(ctx, userID)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *UserRoleHandlerImpl) AssignUserRole(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.UserRoleAssignReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserRole().AssignUserRole(ctx, userID, params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserRoleHandlerImpl) RevokeUserRole(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	roleID, err := ctx.GetPathParamID("roleId")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserRole().RevokeUserRole(ctx, userID, roleID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserRoleHandlerImpl) GetUserEffectiveRoles(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.UserRole().GetUserEffectiveRoles(ctx, userID)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
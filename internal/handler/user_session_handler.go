@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UserSessionHandler interface {
+	GetMySessions(c *gin.Context)
+	RevokeMySession(c *gin.Context)
+	GetUserSessions(c *gin.Context)
+	RevokeUserSession(c *gin.Context)
+}
+
+type UserSessionHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewUserSessionHandler(logger *zap.Logger, service service.Service) UserSessionHandler {
+	return &UserSessionHandlerImpl{logger: logger, service: service}
+}
+
+func (h *UserSessionHandlerImpl) GetMySessions(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	res, err := h.service.UserSession().GetMySessions(ctx)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *UserSessionHandlerImpl) RevokeMySession(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	sessionID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserSession().RevokeMySession(ctx, sessionID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
+
+func (h *UserSessionHandlerImpl) GetUserSessions(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.UserSession().GetUserSessions(ctx, userID)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *UserSessionHandlerImpl) RevokeUserSession(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	sessionID, err := ctx.GetPathParamID("sessionId")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	if err := h.service.UserSession().RevokeUserSession(ctx, userID, sessionID); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(nil)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UserStatusHandler interface {
+	GetStatus(c *gin.Context)
+	SetStatus(c *gin.Context)
+}
+
+type UserStatusHandlerImpl struct {
+	logger  *zap.Logger
+	service service.Service
+}
+
+func NewUserStatusHandler(logger *zap.Logger, service service.Service) UserStatusHandler {
+	return &UserStatusHandlerImpl{logger: logger, service: service}
+}
+
+func (h *UserStatusHandlerImpl) GetStatus(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.UserStatus().GetStatus(ctx, userID)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
+
+func (h *UserStatusHandlerImpl) SetStatus(c *gin.Context) {
+	var ctx = ctx.FromGinCtx(c)
+	userID, err := ctx.GetPathParamID("id")
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	var params dto.UserSetStatusReqDto
+	if err := ctx.ShouldBind(&params); err != nil {
+		ctx.ToError(err)
+		return
+	}
+	res, err := h.service.UserStatus().SetStatus(ctx, userID, params)
+	if err != nil {
+		ctx.ToError(err)
+		return
+	}
+	ctx.ToSuccess(res)
+}
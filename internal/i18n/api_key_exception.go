@@ -0,0 +1,10 @@
+package i18n
+
+var (
+	ExcAPIKeyInvalid       = Text{En: "The API key is invalid", Zh: "API 密钥无效"}
+	ExcAPIKeyExpired       = Text{En: "The API key has expired", Zh: "API 密钥已过期"}
+	ExcAPIKeyRevoked       = Text{En: "The API key has been revoked", Zh: "API 密钥已被撤销"}
+	ExcAPIKeyNotFound      = Text{En: "API key not found", Zh: "API 密钥不存在"}
+	ExcAPIKeyLimitExceeded = Text{En: "The number of API keys has reached the limit", Zh: "API 密钥数量已达上限"}
+	ExcAPIKeyScopeDenied   = Text{En: "The API key does not have the required scope", Zh: "API 密钥没有所需的权限范围"}
+)
@@ -24,4 +24,6 @@ var (
 	ExcServiceError         = Text{En: "Service error", Zh: "服务错误"}
 	ExcServiceTimeout       = Text{En: "Service timeout", Zh: "服务超时"}
 	ExcDatabaseError        = Text{En: "Database error", Zh: "数据库错误"}
+	ExcVersionConflict      = Text{En: "The data has been modified by someone else, please reload and try again", Zh: "数据已被修改，请刷新后重试"}
+	ExcPreconditionRequired = Text{En: "Please reload the data and submit it with its version", Zh: "请刷新数据后携带版本号提交"}
 )
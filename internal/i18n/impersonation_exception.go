@@ -0,0 +1,7 @@
+package i18n
+
+var (
+	ExcImpersonationForbidden     = Text{En: "This operation is not allowed while impersonating a user", Zh: "模拟登录期间不允许此操作"}
+	ExcImpersonationNotActive     = Text{En: "You are not impersonating a user", Zh: "当前不是模拟登录"}
+	ExcImpersonationTargetInvalid = Text{En: "This user cannot be impersonated", Zh: "不能模拟登录该用户"}
+)
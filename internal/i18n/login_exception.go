@@ -0,0 +1,11 @@
+package i18n
+
+var (
+	ExcLoginStepUpInvalid = Text{En: "The login verification is invalid or expired, please login again", Zh: "登录验证已失效，请重新登录"}
+)
+
+// New login notification templates
+var (
+	MsgNewLoginSubject = Text{En: "New sign-in to your account", Zh: "您的账号有新的登录"}
+	MsgNewLoginContent = Text{En: "Your account was signed in at {time} from {ip} ({device}). If this wasn't you, change your password and sign out of all devices.", Zh: "您的账号于 {time} 在 {ip}（{device}）登录。如果不是您本人操作，请立即修改密码并退出所有设备。"}
+)
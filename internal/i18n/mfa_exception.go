@@ -0,0 +1,11 @@
+package i18n
+
+var (
+	ExcMFANotEnrolled              = Text{En: "Two-factor authentication has not been set up", Zh: "尚未设置两步验证"}
+	ExcMFAAlreadyEnabled           = Text{En: "Two-factor authentication is already enabled", Zh: "两步验证已开启"}
+	ExcMFANotEnabled               = Text{En: "Two-factor authentication is not enabled", Zh: "两步验证未开启"}
+	ExcMFACodeIsIncorrect          = Text{En: "The two-factor authentication code is incorrect", Zh: "两步验证码不正确"}
+	ExcMFAChallengeInvalid         = Text{En: "The login challenge is invalid or expired, please login again", Zh: "登录验证已失效，请重新登录"}
+	ExcMFAChallengeTooManyAttempts = Text{En: "Too many incorrect attempts, please login again", Zh: "验证码错误次数过多，请重新登录"}
+	ExcMFARequired                 = Text{En: "Two-factor authentication is required for your role, please enable it first", Zh: "您的角色要求开启两步验证，请先开启"}
+)
@@ -0,0 +1,7 @@
+package i18n
+
+var (
+	ExcOAuthProviderNotFound = Text{En: "Login provider not found", Zh: "不支持该登录方式"}
+	ExcOAuthStateInvalid     = Text{En: "The login request is invalid or expired, please try again", Zh: "登录请求无效或已过期，请重试"}
+	ExcOAuthExchangeFailed   = Text{En: "Third-party login failed, please try again", Zh: "第三方登录失败，请重试"}
+)
@@ -0,0 +1,13 @@
+package i18n
+
+var (
+	ExcOrganizationNotFound       = Text{En: "Organization not found", Zh: "组织不存在"}
+	ExcOrganizationSlugTaken      = Text{En: "The organization identifier is already taken", Zh: "组织标识已被使用"}
+	ExcOrganizationNotMember      = Text{En: "You are not a member of this organization", Zh: "你不是该组织的成员"}
+	ExcOrganizationRoleForbidden  = Text{En: "Your role in this organization does not allow this operation", Zh: "你在该组织中的角色不允许此操作"}
+	ExcOrganizationMemberNotFound = Text{En: "Organization member not found", Zh: "组织成员不存在"}
+	ExcOrganizationMemberExists   = Text{En: "The user is already a member of this organization", Zh: "该用户已是组织成员"}
+	ExcOrganizationLastOwner      = Text{En: "The organization must have at least one owner", Zh: "组织至少需要一个所有者"}
+	ExcOrganizationTenantRequired = Text{En: "Please specify the organization", Zh: "请指定组织"}
+	ExcOrganizationTenantMismatch = Text{En: "The token belongs to another organization", Zh: "令牌属于其他组织"}
+)
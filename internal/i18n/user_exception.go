@@ -11,4 +11,17 @@ var (
 	ExcUserPasswordIsIncorrect               = Text{En: "The password is incorrect", Zh: "密码不正确"}
 	ExcUserNotFound                          = Text{En: "User not found", Zh: "用户不存在"}
 	ExcUserUpdateInfoFailed                  = Text{En: "User update info failed", Zh: "用户信息更新失败"}
+	ExcUserUsernameHasBeenRegistered         = Text{En: "The username has already been registered", Zh: "用户名已注册"}
+	ExcUserPasswordNotSet                    = Text{En: "The password has not been set", Zh: "尚未设置密码"}
+	ExcUserPasswordHasBeenSet                = Text{En: "The password has already been set", Zh: "密码已设置"}
+	ExcUserReauthRequired                    = Text{En: "Please verify your identity first", Zh: "请先验证身份"}
+	ExcUserReauthTokenInvalid                = Text{En: "Identity verification has expired, please verify again", Zh: "身份验证已过期，请重新验证"}
+	ExcUserLastLoginIdentifier               = Text{En: "This is your only login method and cannot be removed", Zh: "这是唯一的登录方式，不能解绑"}
+	ExcUserSuspended                         = Text{En: "Your account has been suspended", Zh: "账号已被暂停使用"}
+	ExcUserBanned                            = Text{En: "Your account has been banned", Zh: "账号已被封禁"}
+	ExcUserDeactivated                       = Text{En: "Your account has been deactivated", Zh: "账号已停用"}
+	ExcUserStatusChangeForbidden             = Text{En: "You do not have permission to change the status of this account", Zh: "无权修改该账号的状态"}
+	ExcUserDeletionNotRequested              = Text{En: "No account deletion has been requested", Zh: "尚未申请注销账号"}
+	ExcUserRestoreConflict                   = Text{En: "The username, email or mobile number is already used by another user", Zh: "用户名、邮箱或手机号已被其他用户使用"}
+	ExcUserNotInTrash                        = Text{En: "User not found in trash", Zh: "回收站中没有该用户"}
 )
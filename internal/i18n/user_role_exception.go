@@ -1,6 +1,9 @@
 package i18n
 
 var (
-	ExcUserRoleNotFound      = Text{En: "User role not found", Zh: "用户角色不存在"}
-	ExcUserRoleAlreadyExists = Text{En: "User role already exists", Zh: "用户角色已存在"}
+	ExcUserRoleNotFound       = Text{En: "User role not found", Zh: "用户角色不存在"}
+	ExcUserRoleAlreadyExists  = Text{En: "User role already exists", Zh: "用户角色已存在"}
+	ExcUserRoleGrantForbidden = Text{En: "Only super administrators can grant or revoke administrator roles", Zh: "只有超级管理员可以授予或撤销管理员角色"}
+	ExcUserRoleLastSuperAdmin = Text{En: "The last super administrator cannot be revoked", Zh: "不能撤销最后一个超级管理员"}
+	ExcUserRoleCannotDisable  = Text{En: "The super administrator role cannot be disabled", Zh: "超级管理员角色不能禁用"}
 )
@@ -0,0 +1,5 @@
+package i18n
+
+var (
+	ExcUserSessionNotFound = Text{En: "User session not found", Zh: "登录会话不存在"}
+)
@@ -0,0 +1,16 @@
+package i18n
+
+var (
+	ExcVerifyCodeNotFoundOrExpired = Text{En: "The verification code does not exist or has expired", Zh: "验证码不存在或已过期"}
+	ExcVerifyCodeUsed              = Text{En: "The verification code has already been used", Zh: "验证码已被使用"}
+	ExcVerifyCodeTooManyAttempts   = Text{En: "Too many incorrect attempts, please request a new verification code", Zh: "验证码错误次数过多，请重新获取"}
+	ExcVerifyCodeSendTooFrequently = Text{En: "Verification code sent too frequently, please try again later", Zh: "验证码发送过于频繁，请稍后再试"}
+	ExcVerifyCodeSendLimitExceeded = Text{En: "Verification code send limit exceeded, please try again later", Zh: "验证码发送次数已达上限，请稍后再试"}
+	ExcVerifyCodeSendFailed        = Text{En: "Verification code send failed", Zh: "验证码发送失败"}
+)
+
+// Verify code message templates
+var (
+	MsgVerifyCodeSubject = Text{En: "Your verification code", Zh: "您的验证码"}
+	MsgVerifyCodeContent = Text{En: "Your verification code is {code}, valid for {minutes} minutes. Do not share it with anyone.", Zh: "您的验证码是 {code}，{minutes} 分钟内有效，请勿泄露给他人。"}
+)
@@ -35,7 +35,6 @@ func DefaultCORSConfig() CORSConfig {
 
 	exposeHeaders := []string{
 		"X-Request-ID",
-		"new-token",
 	}
 
 	return CORSConfig{
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"go-server-starter/internal/constant"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -21,7 +22,7 @@ func ZapLogger(logger *zap.Logger) gin.HandlerFunc {
 		c.Next()
 		timeSpend := time.Since(start)
 		timeSpendMs := float64(timeSpend.Microseconds()) / 1000.0
-		logger.Info("request",
+		fields := []zap.Field{
 			zap.Int("status", c.Writer.Status()),
 			zap.String("method", c.Request.Method),
 			zap.String("path", URL.Path),
@@ -30,7 +31,13 @@ func ZapLogger(logger *zap.Logger) gin.HandlerFunc {
 			zap.String("ip", c.ClientIP()),
 			zap.String("user-agent", c.Request.UserAgent()),
 			zap.Float64("time_spend_ms", timeSpendMs),
-		)
+			zap.String("request_id", c.GetString(constant.CTX_KEY_OF_REQUEST_ID)),
+		}
+		// 模拟登录的请求记录实际操作的管理员
+		if impersonator := c.GetString(constant.CTX_KEY_OF_IMPERSONATOR); impersonator != "" {
+			fields = append(fields, zap.String("impersonator", impersonator))
+		}
+		logger.Info("request", fields...)
 	}
 }
 
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"go-server-starter/internal/repo"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadPrimaryOnWrite 写请求中的查询也读主库, 避免副本延迟导致唯一性和版本检查读到旧数据
+func ReadPrimaryOnWrite() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			c.Request = c.Request.WithContext(repo.WithPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
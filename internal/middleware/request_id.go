@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"go-server-starter/internal/constant"
+	"go-server-starter/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求ID请求头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 沿用客户端传入的请求ID, 没有时生成一个, 并在响应头中返回
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID, _ = utils.NewUUID()
+		}
+		c.Set(constant.CTX_KEY_OF_REQUEST_ID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"go-server-starter/internal/enum"
+	"time"
+)
+
+// API 密钥, 格式为 <prefix>_<KeyID>_<secret>, 只保存 secret 的哈希
+type APIKey struct {
+	Model
+	UserID     uint64          `gorm:"index;not null" json:"userId"`
+	User       User            `json:"-"`
+	Name       string          `gorm:"size:64;not null" json:"name"`
+	Type       enum.APIKeyType `gorm:"size:16;not null" json:"type"`
+	KeyID      string          `gorm:"uniqueIndex;size:32;not null" json:"keyId"` // 公开的密钥标识, 用于查找
+	SecretHash string          `gorm:"size:64;not null" json:"-"`
+	Scopes     []string        `gorm:"serializer:json" json:"scopes"`
+	ExpiresAt  *time.Time      `json:"expiresAt"`
+	LastUsedAt *time.Time      `json:"lastUsedAt"`
+	LastUsedIP string          `json:"lastUsedIp"`
+	RevokedAt  *time.Time      `json:"revokedAt"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
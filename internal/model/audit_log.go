@@ -0,0 +1,24 @@
+package model
+
+import "go-server-starter/internal/enum"
+
+// 审计日志, 记录管理和安全相关操作, Before/After 只保存发生变化的字段
+type AuditLog struct {
+	Model
+	ActorUniCode string               `gorm:"size:64;index" json:"actorUniCode"`                // 操作人
+	Impersonator string               `gorm:"size:64;index" json:"impersonator"`                // 模拟登录时实际操作的管理员
+	Action       string               `gorm:"size:128;index;not null" json:"action"`            // 操作
+	TargetType   enum.AuditTargetType `gorm:"size:32;index:idx_audit_target" json:"targetType"` // 操作对象类型
+	TargetID     string               `gorm:"size:64;index:idx_audit_target" json:"targetId"`   // 操作对象ID
+	Before       map[string]any       `gorm:"serializer:json" json:"before"`
+	After        map[string]any       `gorm:"serializer:json" json:"after"`
+	IP           string               `gorm:"size:64" json:"ip"`
+	DeviceType   string               `gorm:"size:16" json:"deviceType"`
+	DeviceID     string               `gorm:"size:128" json:"deviceId"`
+	UserAgent    string               `gorm:"size:255" json:"userAgent"`
+	RequestID    string               `gorm:"size:64;index" json:"requestId"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "go-server-starter/internal/enum"
+
+// 登录记录, 只记录能确定用户的登录
+type LoginEvent struct {
+	Model
+	UserID     uint64            `gorm:"index;not null" json:"userId"`
+	Method     enum.LoginMethod  `gorm:"size:32;not null" json:"method"`
+	Outcome    enum.LoginOutcome `gorm:"size:16;not null;index" json:"outcome"`
+	Reason     string            `gorm:"size:255" json:"reason"` // 失败原因或挑战类型
+	IP         string            `gorm:"size:64" json:"ip"`
+	IPRange    string            `gorm:"size:64;index" json:"ipRange"` // IP 所在网段, 用于判断新 IP 段
+	UserAgent  string            `gorm:"size:255" json:"userAgent"`
+	DeviceType enum.DeviceType   `gorm:"size:16" json:"deviceType"`
+	DeviceID   string            `gorm:"size:128" json:"deviceId"`
+	NewDevice  bool              `json:"newDevice"`
+	NewIPRange bool              `json:"newIpRange"`
+}
+
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
@@ -14,3 +14,27 @@ type Model struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`           // 删除时间
 	Version   uint64         `gorm:"default:0" json:"version"` // 版本号 用于乐观锁
 }
+
+// Tenanted 按租户隔离的模型, BaseRepo 会自动按 tenant_id 过滤
+type Tenanted interface {
+	GetTenantID() uint64
+	SetTenantID(tenantID uint64)
+}
+
+// Versioned 支持乐观锁的模型, 嵌入 Model 即可
+type Versioned interface {
+	GetVersion() uint64
+	SetVersion(version uint64)
+}
+
+func (m *Model) GetID() uint64 {
+	return m.ID
+}
+
+func (m *Model) GetVersion() uint64 {
+	return m.Version
+}
+
+func (m *Model) SetVersion(version uint64) {
+	m.Version = version
+}
@@ -0,0 +1,36 @@
+package model
+
+import "go-server-starter/internal/enum"
+
+// 组织 即租户, 通过 Slug 在请求头或子域名中识别
+type Organization struct {
+	Model
+	Name    string `gorm:"size:64;not null" json:"name"`
+	Slug    string `gorm:"uniqueIndex;size:32;not null" json:"slug"`
+	OwnerID uint64 `gorm:"index;not null" json:"ownerId"` // 创建者
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// 组织成员, 按组织隔离, TenantID 即组织ID
+type OrganizationMember struct {
+	Model
+	TenantID uint64                `gorm:"uniqueIndex:idx_org_member,priority:1;not null" json:"tenantId"`
+	UserID   uint64                `gorm:"uniqueIndex:idx_org_member,priority:2;index;not null" json:"userId"` // 同一组织内唯一
+	User     User                  `json:"-"`
+	Role     enum.OrganizationRole `gorm:"size:16;not null" json:"role"` // 组织内的角色, 与全局角色相互独立
+}
+
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+func (m *OrganizationMember) GetTenantID() uint64 {
+	return m.TenantID
+}
+
+func (m *OrganizationMember) SetTenantID(tenantID uint64) {
+	m.TenantID = tenantID
+}
@@ -0,0 +1,13 @@
+package model
+
+import "go-server-starter/internal/enum"
+
+// 权限, 通过 user_role_permissions 授予角色
+type Permission struct {
+	Model
+	Code enum.PermissionCode `gorm:"unique;not null" json:"code"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
@@ -1,19 +1,46 @@
 package model
 
+import (
+	"go-server-starter/internal/enum"
+	"time"
+)
+
 type User struct {
 	Model
-	UniCode     string     `gorm:"uniqueIndex;not null" json:"uniCode"`
-	Email       string     `gorm:"index" json:"email"`
-	Mobile      string     `gorm:"index:idx_mobile_country" json:"mobile"`
-	CountryCode string     `gorm:"index:idx_mobile_country" json:"countryCode"`
-	Desc        string     `json:"desc"`
-	Password    string     `json:"-"`
-	Salt        string     `json:"-"`
-	Nickname    string     `gorm:"index" json:"nickname"`
-	AvatarURL   string     `json:"avatarURL"`
-	Roles       []UserRole `gorm:"many2many:user_role_refs;constraint:OnDelete:CASCADE" json:"roles"`
+	UniCode     string         `gorm:"uniqueIndex;not null" json:"uniCode"`
+	Username    string         `gorm:"index" json:"username"`
+	Email       string         `gorm:"index" json:"email"`
+	Mobile      string         `gorm:"index:idx_mobile_country" json:"mobile"`
+	CountryCode string         `gorm:"index:idx_mobile_country" json:"countryCode"`
+	Desc        string         `json:"desc"`
+	Password    string         `json:"-"`
+	Salt        string         `json:"-"`
+	Nickname    string         `gorm:"index" json:"nickname"`
+	AvatarURL   string         `json:"avatarURL"`
+	Roles       []UserRole     `gorm:"many2many:user_role_refs;constraint:OnDelete:CASCADE" json:"roles"`
+	Identities  []UserIdentity `json:"-"`
+	// 账号状态, 非 active 的账号不能登录和访问
+	Status       enum.UserStatus `gorm:"size:16;not null;default:active;index" json:"status"`
+	StatusReason string          `gorm:"size:255" json:"statusReason"`
+	StatusUntil  *time.Time      `json:"statusUntil"` // 暂停的截止时间, 到期后自动恢复
+	StatusSetBy  *uint64         `json:"statusSetBy"` // 设置状态的管理员
+	StatusSetAt  *time.Time      `json:"statusSetAt"`
+	// 注销申请, 冷静期结束后由后台任务清除账号数据
+	DeletionRequestedAt *time.Time `json:"deletionRequestedAt"`
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletionScheduledAt"`
+	// 唯一约束只作用于未删除且非空的值, 软删除的用户和未绑定的空值不会冲突
+	UsernameKey *string `gorm:"->:false;type:varchar(256) GENERATED ALWAYS AS (IF(deleted_at IS NULL AND username <> '', username, NULL)) VIRTUAL;uniqueIndex:idx_username_key" json:"-"`
+	EmailKey    *string `gorm:"->:false;type:varchar(256) GENERATED ALWAYS AS (IF(deleted_at IS NULL AND email <> '', email, NULL)) VIRTUAL;uniqueIndex:idx_email_key" json:"-"`
+	MobileKey   *string `gorm:"->:false;type:varchar(513) GENERATED ALWAYS AS (IF(deleted_at IS NULL AND mobile <> '', CONCAT(country_code, '-', mobile), NULL)) VIRTUAL;uniqueIndex:idx_mobile_key" json:"-"`
 }
 
+// 用户唯一索引名, 用于判断 repo.DuplicateKey 返回的冲突字段
+const (
+	UserUsernameUniqueIndex = "idx_username_key"
+	UserEmailUniqueIndex    = "idx_email_key"
+	UserMobileUniqueIndex   = "idx_mobile_key"
+)
+
 func (User) TableName() string {
 	return "users"
 }
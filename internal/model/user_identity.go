@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// 第三方登录账号, 提供方的 subject 与用户关联
+type UserIdentity struct {
+	Model
+	UserID      uint64     `gorm:"index;not null" json:"userId"`
+	Provider    string     `gorm:"size:32;not null;uniqueIndex:idx_provider_subject" json:"provider"`
+	Subject     string     `gorm:"size:191;not null;uniqueIndex:idx_provider_subject" json:"subject"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	AvatarURL   string     `json:"avatarURL"`
+	LastLoginAt *time.Time `json:"lastLoginAt"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
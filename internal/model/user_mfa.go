@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// TOTP 两步验证, 确认前 Enabled 为 false
+type UserTOTP struct {
+	Model
+	UserID       uint64     `gorm:"uniqueIndex;not null" json:"userId"`
+	Secret       string     `gorm:"size:64;not null" json:"-"`
+	Enabled      bool       `json:"enabled"`
+	ConfirmedAt  *time.Time `json:"confirmedAt"`
+	LastUsedStep int64      `json:"-"` // 最近一次使用的时间步, 防止验证码重放
+}
+
+func (UserTOTP) TableName() string {
+	return "user_totps"
+}
+
+// 两步验证恢复码, 只保存哈希, 每个只能使用一次
+type UserRecoveryCode struct {
+	Model
+	UserID   uint64     `gorm:"index;not null" json:"userId"`
+	CodeHash string     `gorm:"size:64;not null" json:"-"`
+	UsedAt   *time.Time `json:"usedAt"`
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
@@ -4,6 +4,7 @@ import "go-server-starter/internal/enum"
 
 type UserRole struct {
 	Model
-	Code    enum.RoleCode `gorm:"unique;not null" json:"code"`
-	Enabled bool          `gorm:"default:true" json:"enabled"`
+	Code        enum.RoleCode `gorm:"unique;not null" json:"code"`
+	Enabled     bool          `gorm:"default:true" json:"enabled"`
+	Permissions []Permission  `gorm:"many2many:user_role_permissions;constraint:OnDelete:CASCADE" json:"permissions"`
 }
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"go-server-starter/internal/enum"
+	"time"
+)
+
+// 登录会话, 每次登录创建一条, 与令牌家族一一对应
+type UserSession struct {
+	Model
+	UserID     uint64          `gorm:"index;not null" json:"userId"`
+	TokenID    string          `gorm:"uniqueIndex;size:64;not null" json:"-"` // 令牌家族ID
+	DeviceType enum.DeviceType `gorm:"index" json:"deviceType"`
+	DeviceID   string          `json:"deviceId"`
+	DeviceName string          `json:"deviceName"`
+	DeviceInfo string          `json:"deviceInfo"`
+	UserAgent  string          `json:"userAgent"`
+	IP         string          `json:"ip"`
+	LastSeenAt *time.Time      `json:"lastSeenAt"`
+	ExpiresAt  *time.Time      `gorm:"index" json:"expiresAt"`
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepo interface {
+	BaseRepo[model.APIKey]
+}
+
+type APIKeyRepoImpl struct {
+	BaseRepo[model.APIKey]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewAPIKeyRepo(db *gorm.DB, logger *zap.Logger) APIKeyRepo {
+	return &APIKeyRepoImpl{
+		BaseRepo: NewBaseRepo[model.APIKey](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepo interface {
+	BaseRepo[model.AuditLog]
+}
+
+type AuditLogRepoImpl struct {
+	BaseRepo[model.AuditLog]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewAuditLogRepo(db *gorm.DB, logger *zap.Logger) AuditLogRepo {
+	return &AuditLogRepoImpl{
+		BaseRepo: NewBaseRepo[model.AuditLog](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
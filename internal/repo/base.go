@@ -2,8 +2,13 @@ package repo
 
 import (
 	"context"
+	"errors"
+	"go-server-starter/internal/model"
 	"go-server-starter/pkg/utils"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -15,6 +20,11 @@ import (
 * note:
 * - ZeroFields 结构体中的零值会被更新到数据库中
 * - NonZeroFields 结构体中的零值不会被更新到数据库中
+* - 实现 model.Tenanted 的模型按上下文中的租户读写, 没有租户时返回 ErrTenantRequired, 跨租户访问需使用 WithoutTenant
+* - 上下文中有事务 (TxManager.Do) 时自动在该事务中执行
+* - WithVersion 按版本号更新 (乐观锁), 成功时版本号加一, 没有行被更新时返回 ErrVersionConflict
+* - 唯一索引冲突可以用 DuplicateKey 判断冲突的索引
+* - 软删除的数据进入回收站, 可以用 GetTrashed 查询, Restore 恢复, PurgeOlderThan 定期清理
  */
 type BaseRepo[T any] interface {
 	// create 创建数据
@@ -37,6 +47,12 @@ type BaseRepo[T any] interface {
 	UpdateByOptsAndZeroFields(ctx context.Context, where QueryOption, entity *T) error
 	// update by opts and non zero fields 使用opts和非零字段更新数据
 	UpdateByOptsAndNonZeroFields(ctx context.Context, where QueryOption, entity *T) error
+	// update by map with version 使用map按版本号更新数据
+	UpdateByMapWithVersion(ctx context.Context, id uint64, version uint64, entity map[string]any) error
+	// update by zero fields with version 使用零字段按版本号更新数据
+	UpdateByZeroFieldsWithVersion(ctx context.Context, id uint64, version uint64, entity *T) error
+	// update by non zero fields with version 使用非零字段按版本号更新数据
+	UpdateByNonZeroFieldsWithVersion(ctx context.Context, id uint64, version uint64, entity *T) error
 
 	// delete 删除数据
 	SoftDelete(ctx context.Context, id uint64) error
@@ -46,6 +62,12 @@ type BaseRepo[T any] interface {
 	HardDelete(ctx context.Context, id uint64) error
 	// hard delete by ids 硬删除批量数据
 	HardDeleteByIDs(ctx context.Context, ids []uint64) error
+	// restore 恢复软删除的数据, 数据不存在或未删除时返回 gorm.ErrRecordNotFound
+	Restore(ctx context.Context, id uint64) error
+	// restore by ids 恢复批量软删除的数据, 有任一条不存在或未删除时返回 gorm.ErrRecordNotFound
+	RestoreByIDs(ctx context.Context, ids []uint64) error
+	// purge older than 分批硬删除 before 之前软删除的数据, 返回删除的行数
+	PurgeOlderThan(ctx context.Context, before time.Time) (int64, error)
 
 	// get by id 查询单个数据
 	GetByID(ctx context.Context, id uint64, opts ...QueryOption) (*T, error)
@@ -57,6 +79,31 @@ type BaseRepo[T any] interface {
 	GetMany(ctx context.Context, opts ...QueryOption) ([]*T, error)
 	// get table 查询分页数据
 	GetTable(ctx context.Context, page int, pageSize int, opts ...QueryOption) ([]*T, int64, error)
+	// get page 游标分页查询, opts 中不要再指定排序
+	GetPage(ctx context.Context, params CursorPageParams, opts ...QueryOption) (*CursorPage[T], error)
+	// get trashed 分页查询已软删除的数据
+	GetTrashed(ctx context.Context, page int, pageSize int, opts ...QueryOption) ([]*T, int64, error)
+}
+
+// purgeBatchSize 清理软删除数据时每批删除的行数, 避免长时间锁表
+const purgeBatchSize = 1000
+
+// ErrVersionConflict 按版本号更新时数据已被修改或不存在
+var ErrVersionConflict = errors.New("version conflict")
+
+// DuplicateKey 唯一索引冲突时返回冲突的索引名
+func DuplicateKey(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1062 {
+		return "", false
+	}
+	// Duplicate entry 'x' for key 'users.idx_email_key', MySQL 8.0 之前没有表名前缀
+	_, key, _ := strings.Cut(mysqlErr.Message, "for key '")
+	key = strings.TrimSuffix(key, "'")
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	return key, true
 }
 
 type BaseRepoImpl[T any] struct {
@@ -69,7 +116,11 @@ func NewBaseRepo[T any](db *gorm.DB, logger *zap.Logger) BaseRepo[T] {
 }
 
 func (r *BaseRepoImpl[T]) GetByID(ctx context.Context, id uint64, opts ...QueryOption) (*T, error) {
-	db := ApplyQueryOptions(r.db.WithContext(ctx), opts...)
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db = ApplyQueryOptions(db, opts...)
 	var entity T
 	if err := db.First(&entity, id).Error; err != nil {
 		return nil, err
@@ -78,7 +129,11 @@ func (r *BaseRepoImpl[T]) GetByID(ctx context.Context, id uint64, opts ...QueryO
 }
 
 func (r *BaseRepoImpl[T]) GetByIDs(ctx context.Context, ids []uint64, opts ...QueryOption) ([]*T, error) {
-	db := ApplyQueryOptions(r.db.WithContext(ctx), opts...)
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db = ApplyQueryOptions(db, opts...)
 	var entities []*T
 	if err := db.Find(&entities, ids).Error; err != nil {
 		return nil, err
@@ -87,66 +142,205 @@ func (r *BaseRepoImpl[T]) GetByIDs(ctx context.Context, ids []uint64, opts ...Qu
 }
 
 func (r *BaseRepoImpl[T]) Create(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Create(entity).Error
+	if err := assignTenant(ctx, entity); err != nil {
+		return err
+	}
+	return dbWithContext(ctx, r.db).Create(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) CreateBatch(ctx context.Context, entities []*T) error {
-	return r.db.WithContext(ctx).CreateInBatches(entities, 1000).Error
+	if err := assignTenant(ctx, entities...); err != nil {
+		return err
+	}
+	return dbWithContext(ctx, r.db).CreateInBatches(entities, 1000).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateByMap(ctx context.Context, id uint64, entity map[string]any) error {
-	return r.db.WithContext(ctx).Model(new(T)).Where("id = ?", id).Updates(entity).Error
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(T)).Where("id = ?", id).Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateByZeroFields(ctx context.Context, id uint64, entity *T) error {
-	return r.db.WithContext(ctx).Model(new(T)).Where("id = ?", id).Select("*").Updates(entity).Error
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(T)).Where("id = ?", id).Select("*").Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateByNonZeroFields(ctx context.Context, id uint64, entity *T) error {
-	return r.db.WithContext(ctx).Model(new(T)).Where("id = ?", id).Updates(entity).Error
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(T)).Where("id = ?", id).Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateBatchByIDsWithMap(ctx context.Context, ids []uint64, entity map[string]any) error {
-	return r.db.WithContext(ctx).Model(new(T)).Where("id IN ?", ids).Updates(entity).Error
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(T)).Where("id IN ?", ids).Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateBatchByIDsWithZeroFields(ctx context.Context, ids []uint64, entity *T) error {
-	return r.db.WithContext(ctx).Model(new(T)).Where("id IN ?", ids).Select("*").Updates(entity).Error
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(T)).Where("id IN ?", ids).Select("*").Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateBatchByIDsWithNonZeroFields(ctx context.Context, ids []uint64, entity *T) error {
-	return r.db.WithContext(ctx).Model(new(T)).Where("id IN ?", ids).Updates(entity).Error
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Model(new(T)).Where("id IN ?", ids).Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateByOptsAndZeroFields(ctx context.Context, where QueryOption, entity *T) error {
-	db := ApplyQueryOptions(r.db.WithContext(ctx), where)
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	db = ApplyQueryOptions(db, where)
 	return db.Model(new(T)).Select("*").Updates(entity).Error
 }
 
 func (r *BaseRepoImpl[T]) UpdateByOptsAndNonZeroFields(ctx context.Context, where QueryOption, entity *T) error {
-	db := ApplyQueryOptions(r.db.WithContext(ctx), where)
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	db = ApplyQueryOptions(db, where)
 	return db.Model(new(T)).Updates(entity).Error
 }
 
+func (r *BaseRepoImpl[T]) UpdateByMapWithVersion(ctx context.Context, id uint64, version uint64, entity map[string]any) error {
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	updates := make(map[string]any, len(entity)+1)
+	for k, v := range entity {
+		updates[k] = v
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	return checkVersion(db.Model(new(T)).Where("id = ? AND version = ?", id, version).Updates(updates))
+}
+
+func (r *BaseRepoImpl[T]) UpdateByZeroFieldsWithVersion(ctx context.Context, id uint64, version uint64, entity *T) error {
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return r.updateWithVersion(entity, version, func() *gorm.DB {
+		return db.Model(new(T)).Where("id = ? AND version = ?", id, version).Select("*").Updates(entity)
+	})
+}
+
+func (r *BaseRepoImpl[T]) UpdateByNonZeroFieldsWithVersion(ctx context.Context, id uint64, version uint64, entity *T) error {
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	return r.updateWithVersion(entity, version, func() *gorm.DB {
+		return db.Model(new(T)).Where("id = ? AND version = ?", id, version).Updates(entity)
+	})
+}
+
 func (r *BaseRepoImpl[T]) SoftDelete(ctx context.Context, id uint64) error {
-	return r.db.WithContext(ctx).Delete(new(T), id).Error
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Delete(new(T), id).Error
 }
 
 func (r *BaseRepoImpl[T]) SoftDeleteByIDs(ctx context.Context, ids []uint64) error {
-	return r.db.WithContext(ctx).Delete(new(T), ids).Error
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Delete(new(T), ids).Error
 }
 
 func (r *BaseRepoImpl[T]) HardDelete(ctx context.Context, id uint64) error {
-	return r.db.WithContext(ctx).Unscoped().Delete(new(T), id).Error
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Delete(new(T), id).Error
 }
 
 func (r *BaseRepoImpl[T]) HardDeleteByIDs(ctx context.Context, ids []uint64) error {
-	return r.db.WithContext(ctx).Unscoped().Delete(new(T), ids).Error
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Delete(new(T), ids).Error
+}
+
+func (r *BaseRepoImpl[T]) Restore(ctx context.Context, id uint64) error {
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	db = db.Unscoped().Model(new(T)).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *BaseRepoImpl[T]) RestoreByIDs(ctx context.Context, ids []uint64) error {
+	db, err := r.scopedForUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	db = db.Unscoped().Model(new(T)).Where("id IN ? AND deleted_at IS NOT NULL", ids).Update("deleted_at", nil)
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected != int64(len(ids)) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *BaseRepoImpl[T]) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		db, err := r.scoped(ctx)
+		if err != nil {
+			return total, err
+		}
+		db = db.Unscoped().Where("deleted_at < ?", before).Limit(purgeBatchSize).Delete(new(T))
+		if db.Error != nil {
+			return total, db.Error
+		}
+		total += db.RowsAffected
+		if db.RowsAffected < purgeBatchSize {
+			return total, nil
+		}
+	}
 }
 
 func (r *BaseRepoImpl[T]) GetOne(ctx context.Context, opts ...QueryOption) (*T, error) {
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var entity T
-	db := ApplyQueryOptions(r.db.WithContext(ctx), opts...)
+	db = ApplyQueryOptions(db, opts...)
 	if err := db.First(&entity).Error; err != nil {
 		return nil, err
 	}
@@ -155,7 +349,11 @@ func (r *BaseRepoImpl[T]) GetOne(ctx context.Context, opts ...QueryOption) (*T,
 
 func (r *BaseRepoImpl[T]) GetMany(ctx context.Context, opts ...QueryOption) ([]*T, error) {
 	var entities = make([]*T, 0)
-	db := ApplyQueryOptions(r.db.WithContext(ctx), opts...)
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return entities, err
+	}
+	db = ApplyQueryOptions(db, opts...)
 	if err := db.Find(&entities).Error; err != nil {
 		return entities, err
 	}
@@ -166,7 +364,11 @@ func (r *BaseRepoImpl[T]) GetTable(ctx context.Context, page int, pageSize int,
 	page, pageSize = utils.NormalizePageAndPageSize(page, pageSize)
 	var total int64
 	var entities = make([]*T, 0)
-	db := ApplyQueryOptions(r.db.WithContext(ctx), opts...)
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return entities, 0, err
+	}
+	db = ApplyQueryOptions(db, opts...)
 	var entity T
 	if err := db.Model(&entity).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -177,3 +379,46 @@ func (r *BaseRepoImpl[T]) GetTable(ctx context.Context, page int, pageSize int,
 	}
 	return entities, total, nil
 }
+
+func (r *BaseRepoImpl[T]) GetTrashed(ctx context.Context, page int, pageSize int, opts ...QueryOption) ([]*T, int64, error) {
+	return r.GetTable(ctx, page, pageSize, append([]QueryOption{Unscoped(), Where("deleted_at IS NOT NULL")}, opts...)...)
+}
+
+// updateWithVersion 将实体的版本号设为 version+1 后更新, 失败时还原
+func (r *BaseRepoImpl[T]) updateWithVersion(entity *T, version uint64, update func() *gorm.DB) error {
+	versioned, ok := any(entity).(model.Versioned)
+	if !ok {
+		return errors.New("model does not support optimistic locking")
+	}
+	old := versioned.GetVersion()
+	versioned.SetVersion(version + 1)
+	if err := checkVersion(update()); err != nil {
+		versioned.SetVersion(old)
+		return err
+	}
+	return nil
+}
+
+func checkVersion(db *gorm.DB) error {
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// scoped 带上下文的查询, 租户模型按当前租户过滤
+func (r *BaseRepoImpl[T]) scoped(ctx context.Context) (*gorm.DB, error) {
+	return scopeTenant[T](ctx, dbWithContext(ctx, r.db))
+}
+
+// scopedForUpdate 更新时不允许修改 tenant_id
+func (r *BaseRepoImpl[T]) scopedForUpdate(ctx context.Context) (*gorm.DB, error) {
+	db, err := r.scoped(ctx)
+	if err != nil || !isTenanted[T]() {
+		return db, err
+	}
+	return db.Omit("tenant_id"), nil
+}
@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-server-starter/pkg/utils"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor 游标无法解析或与排序字段不一致
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorSort 游标分页的排序字段, 排序列应当非空
+type CursorSort struct {
+	Column string
+	Desc   bool
+}
+
+// CursorPageParams 游标分页参数, Sorts 末尾会自动补上 id 保证顺序唯一
+type CursorPageParams struct {
+	Cursor   string // 上一页返回的 NextCursor 或 PrevCursor, 为空时从第一页开始
+	PageSize int
+	Sorts    []CursorSort
+}
+
+type CursorPage[T any] struct {
+	Items      []*T
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+}
+
+// cursor 游标内容, 包含排序字段的值和翻页方向, 编码为 base64 后对外不透明
+type cursor struct {
+	Sort     string            `json:"s"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// GetPage 游标分页, 不统计总数, 翻页时数据变化也不会跳过或重复
+func (r *BaseRepoImpl[T]) GetPage(ctx context.Context, params CursorPageParams, opts ...QueryOption) (*CursorPage[T], error) {
+	_, pageSize := utils.NormalizePageAndPageSize(1, params.PageSize)
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	// 排序字段可以是字段名或列名, 之后的排序, 游标条件和签名都使用列名
+	fields := make([]*schema.Field, 0, len(params.Sorts)+1)
+	sorts := make([]CursorSort, 0, len(params.Sorts)+1)
+	for _, sort := range params.Sorts {
+		field := stmt.Schema.LookUpField(sort.Column)
+		if field == nil {
+			return nil, fmt.Errorf("unknown sort column %q", sort.Column)
+		}
+		fields = append(fields, field)
+		sorts = append(sorts, CursorSort{Column: field.DBName, Desc: sort.Desc})
+	}
+	if len(sorts) == 0 || sorts[len(sorts)-1].Column != "id" {
+		fields = append(fields, stmt.Schema.LookUpField("id"))
+		sorts = append(sorts, CursorSort{Column: "id", Desc: len(sorts) > 0 && sorts[len(sorts)-1].Desc})
+	}
+
+	db, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db = ApplyQueryOptions(db, opts...)
+	var cur *cursor
+	if params.Cursor != "" {
+		if cur, err = decodeCursor(params.Cursor, sorts); err != nil {
+			return nil, err
+		}
+		values := make([]any, len(fields))
+		for i, field := range fields {
+			value := reflect.New(field.FieldType)
+			if err := json.Unmarshal(cur.Values[i], value.Interface()); err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = value.Elem().Interface()
+		}
+		query, args := keysetCondition(stmt, sorts, fields, values, cur.Backward)
+		db = db.Where(query, args...)
+	}
+	backward := cur != nil && cur.Backward
+	for i, sort := range sorts {
+		// 向前翻页时反向排序, 取到数据后再翻转回来
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: fields[i].DBName}, Desc: sort.Desc != backward})
+	}
+
+	var entities = make([]*T, 0, pageSize+1)
+	if err := db.Limit(pageSize + 1).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	more := len(entities) > pageSize
+	if more {
+		entities = entities[:pageSize]
+	}
+	page := &CursorPage[T]{Items: entities}
+	if backward {
+		slices.Reverse(entities)
+		page.HasPrev, page.HasNext = more, true
+	} else {
+		page.HasPrev, page.HasNext = cur != nil, more
+	}
+	if len(entities) == 0 {
+		return page, nil
+	}
+	if page.HasNext {
+		if page.NextCursor, err = encodeCursor(ctx, sorts, fields, entities[len(entities)-1], false); err != nil {
+			return nil, err
+		}
+	}
+	if page.HasPrev {
+		if page.PrevCursor, err = encodeCursor(ctx, sorts, fields, entities[0], true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// keysetCondition 多列排序的游标条件: (a > ?) OR (a = ? AND b > ?) OR ...
+func keysetCondition(stmt *gorm.Statement, sorts []CursorSort, fields []*schema.Field, values []any, backward bool) (string, []any) {
+	ors := make([]string, len(sorts))
+	var args []any
+	for i, sort := range sorts {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, stmt.Quote(fields[j].DBName)+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if sort.Desc != backward {
+			op = "<"
+		}
+		ands = append(ands, stmt.Quote(fields[i].DBName)+" "+op+" ?")
+		args = append(args, values[i])
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+func encodeCursor[T any](ctx context.Context, sorts []CursorSort, fields []*schema.Field, entity *T, backward bool) (string, error) {
+	cur := cursor{Sort: sortSignature(sorts), Values: make([]json.RawMessage, len(fields)), Backward: backward}
+	rv := reflect.ValueOf(entity)
+	for i, field := range fields {
+		value, _ := field.ValueOf(ctx, rv)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		cur.Values[i] = data
+	}
+	data, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string, sorts []CursorSort) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cur cursor
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	// 排序方式变化后旧游标失效
+	if cur.Sort != sortSignature(sorts) || len(cur.Values) != len(sorts) {
+		return nil, ErrInvalidCursor
+	}
+	return &cur, nil
+}
+
+func sortSignature(sorts []CursorSort) string {
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		parts[i] = sort.Column
+		if sort.Desc {
+			parts[i] = "-" + sort.Column
+		}
+	}
+	return strings.Join(parts, ",")
+}
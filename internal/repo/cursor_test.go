@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type cursorTestItem struct {
+	ID    uint64
+	Score int `gorm:"column:item_score"`
+}
+
+func TestGetPageSortsByFieldName(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&cursorTestItem{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, score := range []int{3, 1, 2, 1, 3} {
+		if err := db.Create(&cursorTestItem{Score: score}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewBaseRepo[cursorTestItem](db, zap.NewNop())
+	ctx := context.Background()
+
+	// 字段名和列名是同一个排序, 游标可以互用
+	var got []uint64
+	params := CursorPageParams{PageSize: 2, Sorts: []CursorSort{{Column: "Score", Desc: true}}}
+	for {
+		page, err := r.GetPage(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+		}
+		if !page.HasNext {
+			break
+		}
+		params.Cursor = page.NextCursor
+		params.Sorts = []CursorSort{{Column: "item_score", Desc: true}}
+	}
+	want := []uint64{5, 1, 3, 4, 2}
+	if len(got) != len(want) {
+		t.Fatalf("ids = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ids = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LoginEventRepo interface {
+	BaseRepo[model.LoginEvent]
+}
+
+type LoginEventRepoImpl struct {
+	BaseRepo[model.LoginEvent]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewLoginEventRepo(db *gorm.DB, logger *zap.Logger) LoginEventRepo {
+	return &LoginEventRepoImpl{
+		BaseRepo: NewBaseRepo[model.LoginEvent](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
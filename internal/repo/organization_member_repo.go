@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OrganizationMemberRepo interface {
+	BaseRepo[model.OrganizationMember]
+}
+
+type OrganizationMemberRepoImpl struct {
+	BaseRepo[model.OrganizationMember]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewOrganizationMemberRepo(db *gorm.DB, logger *zap.Logger) OrganizationMemberRepo {
+	return &OrganizationMemberRepoImpl{
+		BaseRepo: NewBaseRepo[model.OrganizationMember](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
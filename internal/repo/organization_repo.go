@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepo interface {
+	BaseRepo[model.Organization]
+}
+
+type OrganizationRepoImpl struct {
+	BaseRepo[model.Organization]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewOrganizationRepo(db *gorm.DB, logger *zap.Logger) OrganizationRepo {
+	return &OrganizationRepoImpl{
+		BaseRepo: NewBaseRepo[model.Organization](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PermissionRepo interface {
+	BaseRepo[model.Permission]
+}
+
+type PermissionRepoImpl struct {
+	BaseRepo[model.Permission]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewPermissionRepo(db *gorm.DB, logger *zap.Logger) PermissionRepo {
+	return &PermissionRepoImpl{
+		BaseRepo: NewBaseRepo[model.Permission](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
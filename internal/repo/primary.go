@@ -0,0 +1,15 @@
+package repo
+
+import "context"
+
+type primaryCtxKey struct{}
+
+// WithPrimary 之后的查询都读主库, 用于写入后立即读取 (副本可能还没有同步), 事务中总是使用主库
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryCtxKey{}).(bool)
+	return primary
+}
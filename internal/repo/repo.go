@@ -1,8 +1,6 @@
 package repo
 
 import (
-	"context"
-
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -10,24 +8,56 @@ import (
 type Repo interface {
 	DB() *gorm.DB
 	Logger() *zap.Logger
-	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	Tx() TxManager
 	User() UserRepo
 	UserRole() UserRoleRepo
+	UserSession() UserSessionRepo
+	UserTOTP() UserTOTPRepo
+	UserRecoveryCode() UserRecoveryCodeRepo
+	UserIdentity() UserIdentityRepo
+	APIKey() APIKeyRepo
+	Permission() PermissionRepo
+	Organization() OrganizationRepo
+	OrganizationMember() OrganizationMemberRepo
+	AuditLog() AuditLogRepo
+	LoginEvent() LoginEventRepo
 }
 
 type RepoImpl struct {
-	db           *gorm.DB
-	logger       *zap.Logger
-	userRepo     UserRepo
-	userRoleRepo UserRoleRepo
+	db                     *gorm.DB
+	logger                 *zap.Logger
+	txManager              TxManager
+	userRepo               UserRepo
+	userRoleRepo           UserRoleRepo
+	userSessionRepo        UserSessionRepo
+	userTOTPRepo           UserTOTPRepo
+	userRecoveryCodeRepo   UserRecoveryCodeRepo
+	userIdentityRepo       UserIdentityRepo
+	apiKeyRepo             APIKeyRepo
+	permissionRepo         PermissionRepo
+	organizationRepo       OrganizationRepo
+	organizationMemberRepo OrganizationMemberRepo
+	auditLogRepo           AuditLogRepo
+	loginEventRepo         LoginEventRepo
 }
 
 func NewRepo(db *gorm.DB, logger *zap.Logger) Repo {
 	return &RepoImpl{
-		db:           db,
-		logger:       logger,
-		userRepo:     NewUserRepo(db, logger),
-		userRoleRepo: NewUserRoleRepo(db, logger),
+		db:                     db,
+		logger:                 logger,
+		txManager:              NewTxManager(db, logger),
+		userRepo:               NewUserRepo(db, logger),
+		userRoleRepo:           NewUserRoleRepo(db, logger),
+		userSessionRepo:        NewUserSessionRepo(db, logger),
+		userTOTPRepo:           NewUserTOTPRepo(db, logger),
+		userRecoveryCodeRepo:   NewUserRecoveryCodeRepo(db, logger),
+		userIdentityRepo:       NewUserIdentityRepo(db, logger),
+		apiKeyRepo:             NewAPIKeyRepo(db, logger),
+		permissionRepo:         NewPermissionRepo(db, logger),
+		organizationRepo:       NewOrganizationRepo(db, logger),
+		organizationMemberRepo: NewOrganizationMemberRepo(db, logger),
+		auditLogRepo:           NewAuditLogRepo(db, logger),
+		loginEventRepo:         NewLoginEventRepo(db, logger),
 	}
 }
 
@@ -39,8 +69,8 @@ func (r *RepoImpl) Logger() *zap.Logger {
 	return r.logger
 }
 
-func (r *RepoImpl) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
-	return r.db.WithContext(ctx).Transaction(fn)
+func (r *RepoImpl) Tx() TxManager {
+	return r.txManager
 }
 
 func (r *RepoImpl) User() UserRepo {
@@ -50,3 +80,43 @@ func (r *RepoImpl) User() UserRepo {
 func (r *RepoImpl) UserRole() UserRoleRepo {
 	return r.userRoleRepo
 }
+
+func (r *RepoImpl) UserSession() UserSessionRepo {
+	return r.userSessionRepo
+}
+
+func (r *RepoImpl) UserTOTP() UserTOTPRepo {
+	return r.userTOTPRepo
+}
+
+func (r *RepoImpl) UserRecoveryCode() UserRecoveryCodeRepo {
+	return r.userRecoveryCodeRepo
+}
+
+func (r *RepoImpl) UserIdentity() UserIdentityRepo {
+	return r.userIdentityRepo
+}
+
+func (r *RepoImpl) APIKey() APIKeyRepo {
+	return r.apiKeyRepo
+}
+
+func (r *RepoImpl) Permission() PermissionRepo {
+	return r.permissionRepo
+}
+
+func (r *RepoImpl) Organization() OrganizationRepo {
+	return r.organizationRepo
+}
+
+func (r *RepoImpl) OrganizationMember() OrganizationMemberRepo {
+	return r.organizationMemberRepo
+}
+
+func (r *RepoImpl) AuditLog() AuditLogRepo {
+	return r.auditLogRepo
+}
+
+func (r *RepoImpl) LoginEvent() LoginEventRepo {
+	return r.loginEventRepo
+}
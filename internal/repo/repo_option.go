@@ -5,6 +5,7 @@ import (
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
 )
 
 type QueryOption func(*gorm.DB) *gorm.DB
@@ -158,3 +159,33 @@ func ForUpdate() QueryOption {
 		return db.Clauses(clause.Locking{Strength: "UPDATE"})
 	}
 }
+
+// UsePrimary read from the primary instead of a replica, e.g. right after a write
+//
+//	db.Clauses(dbresolver.Write).First(&user, id)
+func UsePrimary() QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Clauses(dbresolver.Write)
+	}
+}
+
+// Unscoped include soft deleted records
+//
+//	db.Unscoped().Where("user_id = ?", 1).Find(&sessions)
+func Unscoped() QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}
+}
+
+// OrderBy order by columns in sequence
+//
+//	db.OrderBy(CursorSort{Column: "created_at", Desc: true}, CursorSort{Column: "id"})
+func OrderBy(sorts ...CursorSort) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, sort := range sorts {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column}, Desc: sort.Desc})
+		}
+		return db
+	}
+}
@@ -0,0 +1,275 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidQuery 查询参数中的字段或操作不在白名单中, 或值无法解析
+var ErrInvalidQuery = errors.New("invalid query")
+
+/*
+*
+* ParseTableQuery 将表格查询参数解析为 QueryOption, 可用的字段由 DTO 的 query 标签声明, 列名不会来自用户输入
+*
+*	Nickname  *string    `form:"nickname" query:"nickname,column=nickname,filter=contains|eq|prefix,sort,search"`
+*	CreatedAt *time.Time `form:"-" query:"createdAt,column=created_at,filter=between|gte|lte,sort"`
+*
+* 支持的参数:
+* - filter[字段][操作]=值, 省略操作 (filter[字段]=值 或 字段=值) 时使用标签中的第一个操作
+* - sort=-createdAt,nickname 前缀 - 表示倒序, 只能使用带 sort 的字段
+* - q=关键字 在带 search 的字段中模糊搜索
+*
+* 操作: eq ne gt gte lt lte in between (逗号分隔) contains prefix suffix null (true/false)
+ */
+func ParseTableQuery(dto any, values url.Values) (*TableQuery, error) {
+	spec, err := loadQuerySpec(reflect.TypeOf(dto))
+	if err != nil {
+		return nil, err
+	}
+	query := &TableQuery{}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := values.Get(key)
+		var name, op string
+		if m := filterKeyPattern.FindStringSubmatch(key); m != nil {
+			name, op = m[1], m[2]
+		} else if _, ok := spec.fields[key]; ok && value != "" {
+			name = key
+		} else {
+			continue
+		}
+		field, ok := spec.fields[name]
+		if !ok || len(field.ops) == 0 {
+			return nil, fmt.Errorf("%w: %s is not filterable", ErrInvalidQuery, name)
+		}
+		if op == "" {
+			op = field.ops[0]
+		}
+		if !slices.Contains(field.ops, op) {
+			return nil, fmt.Errorf("%w: %s does not support %s", ErrInvalidQuery, name, op)
+		}
+		filter, err := field.filter(op, value)
+		if err != nil {
+			return nil, err
+		}
+		query.Filters = append(query.Filters, filter)
+	}
+	if q := strings.TrimSpace(values.Get("q")); q != "" && len(spec.search) > 0 {
+		conditions := make([]string, len(spec.search))
+		args := make([]any, len(spec.search))
+		for i, column := range spec.search {
+			conditions[i] = column + " LIKE ?"
+			args[i] = "%" + escapeLike(q) + "%"
+		}
+		query.Filters = append(query.Filters, Where("("+strings.Join(conditions, " OR ")+")", args...))
+	}
+	if sort := values.Get("sort"); sort != "" {
+		for _, name := range strings.Split(sort, ",") {
+			name = strings.TrimSpace(name)
+			desc := strings.HasPrefix(name, "-")
+			field, ok := spec.fields[strings.TrimPrefix(name, "-")]
+			if !ok || !field.sort {
+				return nil, fmt.Errorf("%w: %s is not sortable", ErrInvalidQuery, strings.TrimPrefix(name, "-"))
+			}
+			query.Sorts = append(query.Sorts, CursorSort{Column: field.column, Desc: desc})
+		}
+	}
+	return query, nil
+}
+
+// TableQuery 解析后的过滤和排序, Sorts 可以直接用于 GetPage
+type TableQuery struct {
+	Filters []QueryOption
+	Sorts   []CursorSort
+}
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[([^\]]+)\](?:\[([^\]]+)\])?$`)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+type querySpec struct {
+	fields map[string]*queryField
+	search []string
+}
+
+type queryField struct {
+	name   string
+	column string
+	ops    []string
+	sort   bool
+	typ    reflect.Type
+}
+
+var querySpecs sync.Map
+
+func loadQuerySpec(t reflect.Type) (*querySpec, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if spec, ok := querySpecs.Load(t); ok {
+		return spec.(*querySpec), nil
+	}
+	spec := &querySpec{fields: map[string]*queryField{}}
+	if err := collectQueryFields(t, spec); err != nil {
+		return nil, err
+	}
+	querySpecs.Store(t, spec)
+	return spec, nil
+}
+
+var queryOps = []string{"eq", "ne", "gt", "gte", "lt", "lte", "in", "between", "contains", "prefix", "suffix", "null"}
+
+func collectQueryFields(t reflect.Type, spec *querySpec) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("query")
+		if !ok {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				if err := collectQueryFields(sf.Type, spec); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		field := &queryField{name: parts[0], column: parts[0], typ: sf.Type}
+		for field.typ.Kind() == reflect.Pointer {
+			field.typ = field.typ.Elem()
+		}
+		for _, part := range parts[1:] {
+			key, value, _ := strings.Cut(part, "=")
+			switch key {
+			case "column":
+				field.column = value
+			case "filter":
+				field.ops = strings.Split(value, "|")
+				for _, op := range field.ops {
+					if !slices.Contains(queryOps, op) {
+						return fmt.Errorf("query tag of %s.%s: unknown filter %q", t.Name(), sf.Name, op)
+					}
+				}
+			case "sort":
+				field.sort = true
+			case "search":
+				spec.search = append(spec.search, field.column)
+			default:
+				return fmt.Errorf("query tag of %s.%s: unknown option %q", t.Name(), sf.Name, key)
+			}
+		}
+		spec.fields[field.name] = field
+	}
+	return nil
+}
+
+func (f *queryField) filter(op string, raw string) (QueryOption, error) {
+	switch op {
+	case "null":
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s[null] must be true or false", ErrInvalidQuery, f.name)
+		}
+		if isNull {
+			return Where(f.column + " IS NULL"), nil
+		}
+		return Where(f.column + " IS NOT NULL"), nil
+	case "contains", "prefix", "suffix":
+		if f.typ.Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: %s does not support %s", ErrInvalidQuery, f.name, op)
+		}
+		pattern := escapeLike(raw)
+		switch op {
+		case "contains":
+			pattern = "%" + pattern + "%"
+		case "prefix":
+			pattern = pattern + "%"
+		case "suffix":
+			pattern = "%" + pattern
+		}
+		return Where(f.column+" LIKE ?", pattern), nil
+	case "in", "between":
+		items := strings.Split(raw, ",")
+		if op == "between" && len(items) != 2 {
+			return nil, fmt.Errorf("%w: %s[between] needs two values", ErrInvalidQuery, f.name)
+		}
+		args := make([]any, len(items))
+		for i, item := range items {
+			value, err := f.parse(strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		if op == "between" {
+			return Where(f.column+" BETWEEN ? AND ?", args...), nil
+		}
+		return Where(f.column+" IN ?", args), nil
+	default:
+		value, err := f.parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		operators := map[string]string{"eq": "=", "ne": "<>", "gt": ">", "gte": ">=", "lt": "<", "lte": "<="}
+		return Where(f.column+" "+operators[op]+" ?", value), nil
+	}
+}
+
+// parse 按字段类型解析值, 时间支持 RFC3339 和 2006-01-02
+func (f *queryField) parse(raw string) (any, error) {
+	invalid := fmt.Errorf("%w: invalid value %q for %s", ErrInvalidQuery, raw, f.name)
+	switch {
+	case f.typ == reflect.TypeOf(time.Time{}):
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		t, err := time.ParseInLocation(time.DateOnly, raw, time.Local)
+		if err != nil {
+			return nil, invalid
+		}
+		return t, nil
+	case f.typ.Kind() == reflect.String:
+		return raw, nil
+	case f.typ.Kind() == reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case f.typ.Kind() >= reflect.Int && f.typ.Kind() <= reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case f.typ.Kind() >= reflect.Uint && f.typ.Kind() <= reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case f.typ.Kind() == reflect.Float32 || f.typ.Kind() == reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%w: %s cannot be filtered", ErrInvalidQuery, f.name)
+	}
+}
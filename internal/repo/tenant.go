@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"go-server-starter/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTenantRequired = errors.New("tenant is required for tenant scoped model")
+	ErrTenantMismatch = errors.New("entity belongs to another tenant")
+)
+
+type tenantCtxKey struct{}
+
+type withoutTenantCtxKey struct{}
+
+// WithTenant 设置当前租户, 实现 model.Tenanted 的模型只能读写该租户的数据
+func WithTenant(ctx context.Context, tenantID uint64) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, tenantID)
+}
+
+// TenantFromContext 获取当前租户
+func TenantFromContext(ctx context.Context) (uint64, bool) {
+	tenantID, ok := ctx.Value(tenantCtxKey{}).(uint64)
+	return tenantID, ok && tenantID != 0
+}
+
+// WithoutTenant 跨租户访问, 只应在明确需要时使用 (如查询用户加入的所有组织)
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTenantCtxKey{}, true)
+}
+
+func isWithoutTenant(ctx context.Context) bool {
+	without, _ := ctx.Value(withoutTenantCtxKey{}).(bool)
+	return without
+}
+
+func isTenanted[T any]() bool {
+	_, ok := any(new(T)).(model.Tenanted)
+	return ok
+}
+
+// scopeTenant 按租户过滤, 没有租户且未声明跨租户时拒绝访问
+func scopeTenant[T any](ctx context.Context, db *gorm.DB) (*gorm.DB, error) {
+	if !isTenanted[T]() || isWithoutTenant(ctx) {
+		return db, nil
+	}
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrTenantRequired
+	}
+	return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: tenantID}), nil
+}
+
+// assignTenant 创建时写入当前租户, 已指定其他租户时拒绝
+func assignTenant[T any](ctx context.Context, entities ...*T) error {
+	if !isTenanted[T]() || isWithoutTenant(ctx) {
+		return nil
+	}
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return ErrTenantRequired
+	}
+	for _, entity := range entities {
+		tenanted := any(entity).(model.Tenanted)
+		if current := tenanted.GetTenantID(); current != 0 && current != tenantID {
+			return ErrTenantMismatch
+		}
+		tenanted.SetTenantID(tenantID)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// txMaxRetries 死锁或序列化失败时最外层事务的最大重试次数
+const txMaxRetries = 3
+
+type txCtxKey struct{}
+
+// txState 上下文中的事务, 嵌套事务 (保存点) 有自己的 afterCommit, 成功后合并到外层
+type txState struct {
+	db          *gorm.DB
+	afterCommit []func(ctx context.Context)
+}
+
+/*
+*
+* TxManager 将事务放在 context 中传递, BaseRepo 会自动使用上下文中的事务
+*
+* note:
+* - 已在事务中时使用保存点嵌套, 内层失败只回滚到保存点
+* - 最外层事务遇到死锁或序列化失败时整体重试, fn 可能执行多次, 事务外的副作用应放在 AfterCommit 中
+ */
+type TxManager interface {
+	// do 在事务中执行 fn, fn 返回错误或 panic 时回滚
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+	// after commit 注册最外层事务提交后执行的函数, 不在事务中时立即执行
+	AfterCommit(ctx context.Context, fn func(ctx context.Context))
+}
+
+type TxManagerImpl struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewTxManager(db *gorm.DB, logger *zap.Logger) TxManager {
+	return &TxManagerImpl{db: db, logger: logger}
+}
+
+func (m *TxManagerImpl) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if parent, ok := ctx.Value(txCtxKey{}).(*txState); ok {
+		state := &txState{}
+		err := parent.db.Transaction(func(tx *gorm.DB) error {
+			state.db = tx
+			return fn(context.WithValue(ctx, txCtxKey{}, state))
+		})
+		if err == nil {
+			parent.afterCommit = append(parent.afterCommit, state.afterCommit...)
+		}
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		state := &txState{}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			state.db = tx
+			return fn(context.WithValue(ctx, txCtxKey{}, state))
+		})
+		if err == nil {
+			for _, hook := range state.afterCommit {
+				hook(ctx)
+			}
+			return nil
+		}
+		if attempt > txMaxRetries || !isRetryableTxError(err) {
+			return err
+		}
+		m.logger.Warn("transaction conflict, retrying", zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt*attempt) * 10 * time.Millisecond):
+		}
+	}
+}
+
+func (m *TxManagerImpl) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if state, ok := ctx.Value(txCtxKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// TxFromContext 上下文中的事务
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	state, ok := ctx.Value(txCtxKey{}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return state.db, true
+}
+
+// dbWithContext 上下文中有事务时使用事务, 否则使用仓库自己的连接, WithPrimary 的上下文只读主库
+func dbWithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	if usePrimary(ctx) {
+		return db.WithContext(ctx).Clauses(dbresolver.Write)
+	}
+	return db.WithContext(ctx)
+}
+
+// isRetryableTxError 死锁 (1213) 或序列化失败 (SQLSTATE 40001)
+func isRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == 1213 || string(mysqlErr.SQLState[:]) == "40001"
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepo interface {
+	BaseRepo[model.UserIdentity]
+}
+
+type UserIdentityRepoImpl struct {
+	BaseRepo[model.UserIdentity]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewUserIdentityRepo(db *gorm.DB, logger *zap.Logger) UserIdentityRepo {
+	return &UserIdentityRepoImpl{
+		BaseRepo: NewBaseRepo[model.UserIdentity](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"go-server-starter/internal/model"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserRecoveryCodeRepo interface {
+	BaseRepo[model.UserRecoveryCode]
+	Use(ctx context.Context, userID uint64, codeHash string) (bool, error)
+	DeleteByUserID(ctx context.Context, userID uint64) error
+}
+
+type UserRecoveryCodeRepoImpl struct {
+	BaseRepo[model.UserRecoveryCode]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewUserRecoveryCodeRepo(db *gorm.DB, logger *zap.Logger) UserRecoveryCodeRepo {
+	return &UserRecoveryCodeRepoImpl{
+		BaseRepo: NewBaseRepo[model.UserRecoveryCode](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
+
+// Use 标记恢复码为已使用, 不存在或已使用时返回 false
+func (r *UserRecoveryCodeRepoImpl) Use(ctx context.Context, userID uint64, codeHash string) (bool, error) {
+	result := dbWithContext(ctx, r.db).Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRecoveryCodeRepoImpl) DeleteByUserID(ctx context.Context, userID uint64) error {
+	return dbWithContext(ctx, r.db).Unscoped().Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error
+}
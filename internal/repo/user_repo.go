@@ -13,7 +13,6 @@ import (
 
 type UserRepo interface {
 	BaseRepo[model.User]
-	WithTx(tx *gorm.DB) UserRepo
 	GenerateUniCode(ctx context.Context) (string, error)
 	GetIDByUniCode(ctx context.Context, uniCode string) (uint64, error)
 	GetByUniCode(ctx context.Context, uniCode string) (*model.User, error)
@@ -35,18 +34,11 @@ func NewUserRepo(db *gorm.DB, logger *zap.Logger) UserRepo {
 	}
 }
 
-func (r *UserRepoImpl) WithTx(tx *gorm.DB) UserRepo {
-	return &UserRepoImpl{
-		BaseRepo: NewBaseRepo[model.User](tx, r.logger),
-		db:       tx,
-		logger:   r.logger,
-	}
-}
-
+// GenerateUniCode 唯一索引包含软删除的用户, 回收站中的 uniCode 也不能重复使用
 func (r *UserRepoImpl) GenerateUniCode(ctx context.Context) (string, error) {
 	for {
 		uniCode := utils.RandomUserCode()
-		exists, err := r.GetOne(ctx, Where("uni_code = ?", uniCode))
+		exists, err := r.GetOne(ctx, Unscoped(), Where("uni_code = ?", uniCode))
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return "", fmt.Errorf("query user by uni code failed: %w", err)
 		}
@@ -58,7 +50,7 @@ func (r *UserRepoImpl) GenerateUniCode(ctx context.Context) (string, error) {
 
 func (r *UserRepoImpl) GetIDByUniCode(ctx context.Context, uniCode string) (uint64, error) {
 	var user model.User
-	if err := r.db.WithContext(ctx).Where("uni_code = ?", uniCode).Preload("Roles").First(&user).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("uni_code = ?", uniCode).Preload("Roles").First(&user).Error; err != nil {
 		return 0, err
 	}
 	return user.ID, nil
@@ -66,7 +58,7 @@ func (r *UserRepoImpl) GetIDByUniCode(ctx context.Context, uniCode string) (uint
 
 func (r *UserRepoImpl) GetByUniCode(ctx context.Context, uniCode string) (*model.User, error) {
 	var user model.User
-	if err := r.db.WithContext(ctx).Where("uni_code = ?", uniCode).Preload("Roles").First(&user).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("uni_code = ?", uniCode).Preload("Roles").First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -74,7 +66,7 @@ func (r *UserRepoImpl) GetByUniCode(ctx context.Context, uniCode string) (*model
 
 func (r *UserRepoImpl) GetRolesByUniCode(ctx context.Context, uniCode string) ([]*model.UserRole, error) {
 	var user model.User
-	if err := r.db.WithContext(ctx).Where("uni_code = ?", uniCode).Preload("Roles").First(&user).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("uni_code = ?", uniCode).Preload("Roles").First(&user).Error; err != nil {
 		return nil, err
 	}
 	roles := make([]*model.UserRole, 0)
@@ -89,7 +81,7 @@ func (r *UserRepoImpl) GetRolesByUniCode(ctx context.Context, uniCode string) ([
 
 func (r *UserRepoImpl) GetRolesByID(ctx context.Context, id uint64) ([]*model.UserRole, error) {
 	var user model.User
-	if err := r.db.WithContext(ctx).Where("id = ?", id).Preload("Roles").First(&user).Error; err != nil {
+	if err := dbWithContext(ctx, r.db).Where("id = ?", id).Preload("Roles").First(&user).Error; err != nil {
 		return nil, err
 	}
 	roles := make([]*model.UserRole, 0)
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"go-server-starter/internal/model"
 
 	"go.uber.org/zap"
@@ -9,7 +10,11 @@ import (
 
 type UserRoleRepo interface {
 	BaseRepo[model.UserRole]
-	WithTx(tx *gorm.DB) UserRoleRepo
+	GetUserUniCodesByRoleID(ctx context.Context, roleID uint64) ([]string, error)
+	AssignToUser(ctx context.Context, userID uint64, role *model.UserRole) error
+	RevokeFromUser(ctx context.Context, userID uint64, role *model.UserRole) error
+	RevokeAllFromUser(ctx context.Context, userID uint64) error
+	GrantPermissions(ctx context.Context, role *model.UserRole, permissions []*model.Permission) error
 }
 
 type UserRoleRepoImpl struct {
@@ -26,10 +31,34 @@ func NewUserRoleRepo(db *gorm.DB, logger *zap.Logger) UserRoleRepo {
 	}
 }
 
-func (r *UserRoleRepoImpl) WithTx(tx *gorm.DB) UserRoleRepo {
-	return &UserRoleRepoImpl{
-		BaseRepo: NewBaseRepo[model.UserRole](tx, r.logger),
-		db:       tx,
-		logger:   r.logger,
+// GetUserUniCodesByRoleID 获取拥有该角色的所有用户唯一码
+func (r *UserRoleRepoImpl) GetUserUniCodesByRoleID(ctx context.Context, roleID uint64) ([]string, error) {
+	var uniCodes []string
+	err := dbWithContext(ctx, r.db).Model(&model.User{}).
+		Joins("JOIN user_role_refs ON user_role_refs.user_id = users.id").
+		Where("user_role_refs.user_role_id = ?", roleID).
+		Pluck("users.uni_code", &uniCodes).Error
+	if err != nil {
+		return nil, err
 	}
+	return uniCodes, nil
+}
+
+func (r *UserRoleRepoImpl) AssignToUser(ctx context.Context, userID uint64, role *model.UserRole) error {
+	user := &model.User{Model: model.Model{ID: userID}}
+	return dbWithContext(ctx, r.db).Model(user).Association("Roles").Append(role)
+}
+
+func (r *UserRoleRepoImpl) RevokeFromUser(ctx context.Context, userID uint64, role *model.UserRole) error {
+	user := &model.User{Model: model.Model{ID: userID}}
+	return dbWithContext(ctx, r.db).Model(user).Association("Roles").Delete(role)
+}
+
+func (r *UserRoleRepoImpl) RevokeAllFromUser(ctx context.Context, userID uint64) error {
+	user := &model.User{Model: model.Model{ID: userID}}
+	return dbWithContext(ctx, r.db).Model(user).Association("Roles").Clear()
+}
+
+func (r *UserRoleRepoImpl) GrantPermissions(ctx context.Context, role *model.UserRole, permissions []*model.Permission) error {
+	return dbWithContext(ctx, r.db).Model(role).Association("Permissions").Append(permissions)
 }
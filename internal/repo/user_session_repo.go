@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserSessionRepo interface {
+	BaseRepo[model.UserSession]
+}
+
+type UserSessionRepoImpl struct {
+	BaseRepo[model.UserSession]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewUserSessionRepo(db *gorm.DB, logger *zap.Logger) UserSessionRepo {
+	return &UserSessionRepoImpl{
+		BaseRepo: NewBaseRepo[model.UserSession](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+	"go-server-starter/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserTOTPRepo interface {
+	BaseRepo[model.UserTOTP]
+	UseStep(ctx context.Context, id uint64, step int64) (bool, error)
+}
+
+type UserTOTPRepoImpl struct {
+	BaseRepo[model.UserTOTP]
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewUserTOTPRepo(db *gorm.DB, logger *zap.Logger) UserTOTPRepo {
+	return &UserTOTPRepoImpl{
+		BaseRepo: NewBaseRepo[model.UserTOTP](db, logger),
+		db:       db,
+		logger:   logger,
+	}
+}
+
+// UseStep 记录已使用的时间步, 时间步不大于上次使用的时返回 false
+func (r *UserTOTPRepoImpl) UseStep(ctx context.Context, id uint64, step int64) (bool, error) {
+	result := dbWithContext(ctx, r.db).Model(&model.UserTOTP{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"go-server-starter/internal/enum"
+)
+
+func (r *Router) SetupAdminRoutes() {
+	router := r.router.Group("/admin")
+	router.Use(r.auth.Authenticate())
+	{
+		// 角色管理
+		router.GET("/roles", r.auth.RequirePermission(enum.PermissionCodeRoleRead), r.handler.UserRole().GetRoles)
+		router.PUT("/roles/:id/enabled", r.auth.RequirePermission(enum.PermissionCodeRoleManage), r.auth.Audit(enum.AuditTargetTypeRole), r.handler.UserRole().SetRoleEnabled)
+		router.GET("/users/:id/roles", r.auth.RequirePermission(enum.PermissionCodeRoleRead), r.handler.UserRole().GetUserRoles)
+		router.GET("/users/:id/effective-roles", r.auth.RequirePermission(enum.PermissionCodeRoleRead), r.handler.UserRole().GetUserEffectiveRoles)
+		router.POST("/users/:id/roles", r.auth.RequirePermission(enum.PermissionCodeRoleManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.UserRole().AssignUserRole)
+		router.DELETE("/users/:id/roles/:roleId", r.auth.RequirePermission(enum.PermissionCodeRoleManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.UserRole().RevokeUserRole)
+		// 账号状态
+		router.GET("/users/:id/status", r.auth.RequirePermission(enum.PermissionCodeUserManage), r.handler.UserStatus().GetStatus)
+		router.PUT("/users/:id/status", r.auth.RequirePermission(enum.PermissionCodeUserManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.UserStatus().SetStatus)
+		// 回收站
+		router.DELETE("/users/:id", r.auth.RequirePermission(enum.PermissionCodeUserManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.Trash().DeleteUser)
+		router.GET("/users/trash", r.auth.RequirePermission(enum.PermissionCodeUserManage), r.handler.Trash().GetTrashedUsers)
+		router.POST("/users/restore", r.auth.RequirePermission(enum.PermissionCodeUserManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.Trash().RestoreUsers)
+		router.POST("/users/:id/restore", r.auth.RequirePermission(enum.PermissionCodeUserManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.Trash().RestoreUser)
+		// 审计日志
+		router.GET("/audit-logs", r.auth.RequirePermission(enum.PermissionCodeAuditRead), r.handler.AuditLog().GetTable)
+	}
+
+	// 模拟登录只允许超级管理员使用 JWT 发起
+	impersonation := r.router.Group("/admin")
+	impersonation.Use(r.auth.JWT(), r.auth.NoImpersonation())
+	{
+		impersonation.POST("/users/:id/impersonate", r.auth.RoleCheckAny(enum.RoleCodeSuperAdmin), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.Impersonation().Start)
+	}
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"go-server-starter/internal/enum"
+)
+
+// API 密钥的管理只允许登录用户操作, 不能用 API 密钥或模拟登录再创建密钥
+func (r *Router) SetupAPIKeyRoutes() {
+	router := r.router.Group("/user")
+	router.Use(r.auth.JWT(), r.auth.NoImpersonation())
+	{
+		router.GET("/api-keys", r.handler.APIKey().GetMyKeys)
+		router.POST("/api-keys", r.handler.APIKey().CreateMyKey)
+		router.PUT("/api-keys/:id", r.handler.APIKey().UpdateMyKey)
+		router.DELETE("/api-keys/:id", r.handler.APIKey().RevokeMyKey)
+		// Admin
+		router.GET("/admin/:id/api-keys", r.auth.RequirePermission(enum.PermissionCodeApiKeyManage), r.handler.APIKey().GetUserKeys)
+		router.POST("/admin/:id/api-keys", r.auth.RequirePermission(enum.PermissionCodeApiKeyManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.APIKey().CreateUserKey)
+		router.PUT("/admin/:id/api-keys/:keyId", r.auth.RequirePermission(enum.PermissionCodeApiKeyManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.APIKey().UpdateUserKey)
+		router.DELETE("/admin/:id/api-keys/:keyId", r.auth.RequirePermission(enum.PermissionCodeApiKeyManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.APIKey().RevokeUserKey)
+	}
+}
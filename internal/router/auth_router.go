@@ -1,6 +1,31 @@
 package router
 
 func (r *Router) SetupAuthRoutes() {
+	r.router.POST("/auth/code/send/mobile", r.handler.Auth().SendMobileCode)
+	r.router.POST("/auth/code/send/email", r.handler.Auth().SendEmailCode)
 	r.router.POST("/auth/login/by-mobile-and-code", r.handler.Auth().LoginByMobileAndCode)
 	r.router.POST("/auth/login/by-email-and-code", r.handler.Auth().LoginByEmailAndCode)
+	r.router.POST("/auth/register", r.handler.Auth().Register)
+	r.router.POST("/auth/login/by-email-and-password", r.handler.Auth().LoginByEmailAndPassword)
+	r.router.POST("/auth/login/by-mobile-and-password", r.handler.Auth().LoginByMobileAndPassword)
+	r.router.POST("/auth/login/by-username-and-password", r.handler.Auth().LoginByUsernameAndPassword)
+	r.router.POST("/auth/login/step-up", r.handler.Auth().VerifyStepUp)
+	r.router.POST("/auth/refresh", r.handler.Auth().RefreshToken)
+	r.router.POST("/auth/logout", r.auth.JWT(), r.handler.Auth().Logout)
+	r.router.POST("/auth/logout-all", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.Auth().LogoutAll)
+	r.router.POST("/auth/password", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.Auth().SetPassword)
+	r.router.PUT("/auth/password", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.Auth().ChangePassword)
+	// 第三方登录
+	r.router.GET("/auth/oauth/providers", r.handler.Auth().GetOAuthProviders)
+	r.router.GET("/auth/oauth/:provider/authorize", r.handler.Auth().OAuthAuthorize)
+	r.router.POST("/auth/oauth/:provider/callback", r.handler.Auth().OAuthCallback)
+	// 两步验证
+	r.router.POST("/auth/2fa/verify", r.handler.Auth().VerifyMFA)
+	r.router.GET("/auth/2fa", r.auth.JWT(), r.handler.MFA().GetStatus)
+	r.router.POST("/auth/2fa/totp/enroll", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.MFA().EnrollTOTP)
+	r.router.POST("/auth/2fa/totp/confirm", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.MFA().ConfirmTOTP)
+	r.router.POST("/auth/2fa/totp/disable", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.MFA().DisableTOTP)
+	r.router.POST("/auth/2fa/recovery-codes", r.auth.JWT(), r.auth.NoImpersonation(), r.handler.MFA().RegenerateRecoveryCodes)
+	// 结束模拟登录
+	r.router.POST("/auth/impersonation/end", r.auth.JWT(), r.handler.Impersonation().End)
 }
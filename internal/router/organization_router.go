@@ -0,0 +1,28 @@
+package router
+
+import (
+	"go-server-starter/internal/enum"
+)
+
+func (r *Router) SetupOrganizationRoutes() {
+	router := r.router.Group("/organizations")
+	router.Use(r.auth.Authenticate())
+	{
+		router.GET("", r.auth.RequirePermission(enum.PermissionCodeOrgRead), r.handler.Organization().GetMine)
+		router.POST("", r.auth.RequirePermission(enum.PermissionCodeOrgWrite), r.handler.Organization().Create)
+	}
+
+	// 当前组织, 通过请求头或子域名识别
+	// 全局权限同时限制 API 密钥的权限范围, 组织内的角色由 RequireRole 检查
+	org := r.router.Group("/org")
+	org.Use(r.auth.Authenticate(), r.tenant.Resolve())
+	{
+		org.GET("", r.auth.RequirePermission(enum.PermissionCodeOrgRead), r.handler.Organization().GetCurrent)
+		// 模拟登录的令牌不能换取普通令牌
+		org.POST("/token", r.auth.NoImpersonation(), r.auth.RequirePermission(enum.PermissionCodeOrgRead), r.handler.Organization().IssueToken)
+		org.GET("/members", r.auth.RequirePermission(enum.PermissionCodeOrgRead), r.handler.Organization().GetMembers)
+		org.POST("/members", r.auth.RequirePermission(enum.PermissionCodeOrgWrite), r.tenant.RequireRole(enum.OrganizationRoleOwner, enum.OrganizationRoleAdmin), r.handler.Organization().AddMember)
+		org.PUT("/members/:id", r.auth.RequirePermission(enum.PermissionCodeOrgWrite), r.tenant.RequireRole(enum.OrganizationRoleOwner, enum.OrganizationRoleAdmin), r.handler.Organization().UpdateMember)
+		org.DELETE("/members/:id", r.auth.RequirePermission(enum.PermissionCodeOrgWrite), r.tenant.RequireRole(enum.OrganizationRoleOwner, enum.OrganizationRoleAdmin), r.handler.Organization().RemoveMember)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"go-server-starter/internal/middleware"
 	"go-server-starter/pkg/auth"
 	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/tenant"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,16 +13,21 @@ import (
 type Router struct {
 	handler   handler.Handler
 	router    *gin.RouterGroup
+	root      *gin.RouterGroup
 	jwt       *jwt.JWT
 	auth      auth.Auth
+	tenant    tenant.Resolver
 	ratelimit *middleware.RateLimit
 }
 
-func NewRouter(handler handler.Handler, router *gin.RouterGroup, jwt *jwt.JWT, auth auth.Auth, ratelimit *middleware.RateLimit) *Router {
-	return &Router{handler: handler, router: router, jwt: jwt, auth: auth, ratelimit: ratelimit}
+func NewRouter(handler handler.Handler, router *gin.RouterGroup, root *gin.RouterGroup, jwt *jwt.JWT, auth auth.Auth, tenant tenant.Resolver, ratelimit *middleware.RateLimit) *Router {
+	return &Router{handler: handler, router: router, root: root, jwt: jwt, auth: auth, tenant: tenant, ratelimit: ratelimit}
 }
 
 func (r *Router) SetupRoutes() {
+	// JWKS (不带 API 前缀)
+	r.root.GET("/.well-known/jwks.json", r.jwt.JWKSHandler())
+
 	// Hello (公开接口)
 	r.router.GET("/hello", r.handler.Hello().Hello)
 
@@ -30,4 +36,13 @@ func (r *Router) SetupRoutes() {
 
 	// User - 用户相关
 	r.SetupUserRoutes()
+
+	// API Key - API 密钥
+	r.SetupAPIKeyRoutes()
+
+	// Organization - 组织
+	r.SetupOrganizationRoutes()
+
+	// Admin - 管理后台
+	r.SetupAdminRoutes()
 }
@@ -6,11 +6,34 @@ import (
 
 func (r *Router) SetupUserRoutes() {
 	router := r.router.Group("/user")
-	router.Use(r.jwt.JWT())
+	router.Use(r.auth.Authenticate())
 	{
-		router.GET("/info", r.handler.User().GetInfo)
-		router.PUT("/info", r.handler.User().UpdateInfo)
+		router.GET("/info", r.auth.RequirePermission(enum.PermissionCodeUserRead), r.handler.User().GetInfo)
+		router.PUT("/info", r.auth.RequirePermission(enum.PermissionCodeUserWrite), r.handler.User().UpdateInfo)
+		router.GET("/sessions", r.auth.RequirePermission(enum.PermissionCodeUserRead), r.handler.UserSession().GetMySessions)
+		router.DELETE("/sessions/:id", r.auth.RequirePermission(enum.PermissionCodeUserWrite), r.handler.UserSession().RevokeMySession)
+		router.GET("/login-history", r.auth.RequirePermission(enum.PermissionCodeUserRead), r.handler.LoginEvent().GetMyHistory)
 		// Admin User
-		router.GET("/admin/table", r.auth.RoleCheckAny(enum.RoleCodeAdmin, enum.RoleCodeSuperAdmin), r.handler.User().GetTable)
+		router.GET("/admin/table", r.auth.RequirePermission(enum.PermissionCodeUserList), r.handler.User().GetTable)
+		router.GET("/admin/:id/sessions", r.auth.RequirePermission(enum.PermissionCodeSessionManage), r.handler.UserSession().GetUserSessions)
+		router.DELETE("/admin/:id/sessions/:sessionId", r.auth.RequirePermission(enum.PermissionCodeSessionManage), r.auth.Audit(enum.AuditTargetTypeUser), r.handler.UserSession().RevokeUserSession)
+	}
+
+	// 绑定邮箱和手机, 导出数据和注销只允许登录用户操作, 更换, 解绑和注销需要先验证身份
+	account := r.router.Group("/user")
+	account.Use(r.auth.JWT(), r.auth.NoImpersonation())
+	{
+		account.POST("/reauth/code", r.handler.UserAccount().SendReauthCode)
+		account.POST("/reauth", r.handler.UserAccount().Reauth)
+		account.POST("/email/code", r.handler.UserAccount().SendEmailBindCode)
+		account.PUT("/email", r.handler.UserAccount().BindEmail)
+		account.DELETE("/email", r.handler.UserAccount().UnbindEmail)
+		account.POST("/mobile/code", r.handler.UserAccount().SendMobileBindCode)
+		account.PUT("/mobile", r.handler.UserAccount().BindMobile)
+		account.DELETE("/mobile", r.handler.UserAccount().UnbindMobile)
+		account.GET("/export", r.handler.PersonalData().Export)
+		account.GET("/deletion", r.handler.PersonalData().GetDeletion)
+		account.POST("/deletion", r.handler.PersonalData().RequestDeletion)
+		account.DELETE("/deletion", r.handler.PersonalData().CancelDeletion)
 	}
 }
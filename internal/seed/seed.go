@@ -26,6 +26,9 @@ func (s *seed) Run() error {
 	if err := s.SeedUserRole(); err != nil {
 		return err
 	}
+	if err := s.SeedPermission(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -78,3 +81,97 @@ func (s *seed) SeedUserRole() error {
 
 	return nil
 }
+
+var permissions = []enum.PermissionCode{
+	enum.PermissionCodeUserRead,
+	enum.PermissionCodeUserWrite,
+	enum.PermissionCodeUserList,
+	enum.PermissionCodeSessionManage,
+	enum.PermissionCodeApiKeyManage,
+	enum.PermissionCodeRoleRead,
+	enum.PermissionCodeRoleManage,
+	enum.PermissionCodeUserManage,
+	enum.PermissionCodeAuditRead,
+	enum.PermissionCodeOrgRead,
+	enum.PermissionCodeOrgWrite,
+}
+
+// 角色的默认权限, 只在权限首次创建时授予, 之后的调整不会被覆盖
+var defaultRolePermissions = map[enum.RoleCode][]enum.PermissionCode{
+	enum.RoleCodeSuperAdmin: permissions,
+	enum.RoleCodeAdmin: {
+		enum.PermissionCodeUserRead,
+		enum.PermissionCodeUserWrite,
+		enum.PermissionCodeUserList,
+		enum.PermissionCodeSessionManage,
+		enum.PermissionCodeApiKeyManage,
+		enum.PermissionCodeRoleRead,
+		enum.PermissionCodeRoleManage,
+		enum.PermissionCodeUserManage,
+		enum.PermissionCodeAuditRead,
+		enum.PermissionCodeOrgRead,
+		enum.PermissionCodeOrgWrite,
+	},
+	enum.RoleCodeUser:     {enum.PermissionCodeUserRead, enum.PermissionCodeUserWrite, enum.PermissionCodeOrgRead, enum.PermissionCodeOrgWrite},
+	enum.RoleCodeUserVip:  {enum.PermissionCodeUserRead, enum.PermissionCodeUserWrite, enum.PermissionCodeOrgRead, enum.PermissionCodeOrgWrite},
+	enum.RoleCodeUserSvip: {enum.PermissionCodeUserRead, enum.PermissionCodeUserWrite, enum.PermissionCodeOrgRead, enum.PermissionCodeOrgWrite},
+	enum.RoleCodeGuest:    {enum.PermissionCodeUserRead, enum.PermissionCodeOrgRead},
+}
+
+func (s *seed) SeedPermission() error {
+	var ctx = context.Background()
+
+	existingPermissions, err := s.repo.Permission().GetMany(ctx)
+	if err != nil {
+		s.logger.Error("failed to query existing permissions", zap.Error(err))
+		return err
+	}
+	existingPermissionMap := make(map[enum.PermissionCode]bool)
+	for _, permission := range existingPermissions {
+		existingPermissionMap[permission.Code] = true
+	}
+
+	var newPermissions []*model.Permission
+	for _, code := range permissions {
+		if !existingPermissionMap[code] {
+			newPermissions = append(newPermissions, &model.Permission{Code: code})
+		}
+	}
+	if len(newPermissions) == 0 {
+		s.logger.Info("all permissions already exist, no need to seed")
+		return nil
+	}
+
+	return s.repo.Tx().Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Permission().CreateBatch(ctx, newPermissions); err != nil {
+			s.logger.Error("failed to insert permissions", zap.Error(err))
+			return err
+		}
+		newPermissionMap := make(map[enum.PermissionCode]*model.Permission, len(newPermissions))
+		for _, permission := range newPermissions {
+			newPermissionMap[permission.Code] = permission
+		}
+		roles, err := s.repo.UserRole().GetMany(ctx)
+		if err != nil {
+			return err
+		}
+		// 新权限授予默认拥有它的角色
+		for _, role := range roles {
+			var grants []*model.Permission
+			for _, code := range defaultRolePermissions[role.Code] {
+				if permission, ok := newPermissionMap[code]; ok {
+					grants = append(grants, permission)
+				}
+			}
+			if len(grants) == 0 {
+				continue
+			}
+			if err := s.repo.UserRole().GrantPermissions(ctx, role, grants); err != nil {
+				s.logger.Error("failed to grant permissions", zap.String("role", role.Code.String()), zap.Error(err))
+				return err
+			}
+		}
+		s.logger.Info("successfully seeded permissions", zap.Int("count", len(newPermissions)))
+		return nil
+	})
+}
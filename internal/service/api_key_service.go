@@ -0,0 +1,315 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 未指定 scopes 时的默认值, 表示拥有用户的全部权限
+const APIKeyScopeAll = "*"
+
+type APIKeyService interface {
+	GetMyKeys(ctx *ctx.Context) ([]*dto.APIKeyResDto, *exception.Exception)
+	CreateMyKey(ctx *ctx.Context, params *dto.APIKeyCreateReqDto) (*dto.APIKeyCreateResDto, *exception.Exception)
+	UpdateMyKey(ctx *ctx.Context, keyID uint64, params *dto.APIKeyUpdateReqDto) *exception.Exception
+	RevokeMyKey(ctx *ctx.Context, keyID uint64) *exception.Exception
+	GetUserKeys(ctx *ctx.Context, userID uint64) ([]*dto.APIKeyResDto, *exception.Exception)
+	CreateUserKey(ctx *ctx.Context, userID uint64, params *dto.APIKeyAdminCreateReqDto) (*dto.APIKeyCreateResDto, *exception.Exception)
+	UpdateUserKey(ctx *ctx.Context, userID uint64, keyID uint64, params *dto.APIKeyUpdateReqDto) *exception.Exception
+	RevokeUserKey(ctx *ctx.Context, userID uint64, keyID uint64) *exception.Exception
+	Authenticate(ctx *ctx.Context, rawKey string) (*model.APIKey, *exception.Exception)
+}
+
+type APIKeyServiceImpl struct {
+	repo       repo.Repo
+	userStatus UserStatusService
+	config     *config.APIKeyConfig
+	logger     *zap.Logger
+}
+
+func NewAPIKeyService(repo repo.Repo, userStatus UserStatusService, config *config.APIKeyConfig, logger *zap.Logger) APIKeyService {
+	return &APIKeyServiceImpl{
+		repo:       repo,
+		userStatus: userStatus,
+		config:     config,
+		logger:     logger,
+	}
+}
+
+func (s *APIKeyServiceImpl) GetMyKeys(ctx *ctx.Context) ([]*dto.APIKeyResDto, *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	return s.GetUserKeys(ctx, userID)
+}
+
+func (s *APIKeyServiceImpl) CreateMyKey(ctx *ctx.Context, params *dto.APIKeyCreateReqDto) (*dto.APIKeyCreateResDto, *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	return s.create(ctx, userID, enum.APIKeyTypePersonal, params)
+}
+
+func (s *APIKeyServiceImpl) UpdateMyKey(ctx *ctx.Context, keyID uint64, params *dto.APIKeyUpdateReqDto) *exception.Exception {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return exc
+	}
+	return s.UpdateUserKey(ctx, userID, keyID, params)
+}
+
+func (s *APIKeyServiceImpl) RevokeMyKey(ctx *ctx.Context, keyID uint64) *exception.Exception {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return exc
+	}
+	return s.RevokeUserKey(ctx, userID, keyID)
+}
+
+func (s *APIKeyServiceImpl) GetUserKeys(ctx *ctx.Context, userID uint64) ([]*dto.APIKeyResDto, *exception.Exception) {
+	keys, err := s.repo.APIKey().GetMany(ctx.Ctx, repo.Where("user_id = ?", userID), repo.Order("created_at DESC"))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	res := make([]*dto.APIKeyResDto, len(keys))
+	for i, key := range keys {
+		res[i] = toAPIKeyResDto(key)
+	}
+	return res, nil
+}
+
+func (s *APIKeyServiceImpl) CreateUserKey(ctx *ctx.Context, userID uint64, params *dto.APIKeyAdminCreateReqDto) (*dto.APIKeyCreateResDto, *exception.Exception) {
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	keyType := enum.APIKeyTypePersonal
+	if params.Type != "" {
+		keyType = enum.APIKeyType(params.Type)
+	}
+	return s.create(ctx, userID, keyType, &params.APIKeyCreateReqDto)
+}
+
+func (s *APIKeyServiceImpl) UpdateUserKey(ctx *ctx.Context, userID uint64, keyID uint64, params *dto.APIKeyUpdateReqDto) *exception.Exception {
+	key, exc := s.getUserKey(ctx, userID, keyID)
+	if exc != nil {
+		return exc
+	}
+	// scopes 使用 json 序列化, 需要通过结构体更新
+	updates := &model.APIKey{}
+	if params.Name != nil {
+		updates.Name = *params.Name
+	}
+	if params.Scopes != nil {
+		if exc := validateScopes(params.Scopes); exc != nil {
+			return exc
+		}
+		updates.Scopes = normalizeScopes(params.Scopes)
+	}
+	if err := s.repo.APIKey().UpdateByNonZeroFields(ctx.Ctx, key.ID, updates); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *APIKeyServiceImpl) RevokeUserKey(ctx *ctx.Context, userID uint64, keyID uint64) *exception.Exception {
+	key, exc := s.getUserKey(ctx, userID, keyID)
+	if exc != nil {
+		return exc
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	if err := s.repo.APIKey().UpdateByMap(ctx.Ctx, key.ID, map[string]any{"revoked_at": time.Now()}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+// Authenticate 校验 X-API-Key, 返回预加载了所属用户的密钥
+func (s *APIKeyServiceImpl) Authenticate(ctx *ctx.Context, rawKey string) (*model.APIKey, *exception.Exception) {
+	keyID, secret, ok := s.parseKey(rawKey)
+	if !ok {
+		return nil, exception.APIKeyInvalid
+	}
+	key, err := s.repo.APIKey().GetOne(ctx.Ctx, repo.Where("key_id = ?", keyID), repo.Preload("User"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if key == nil || subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(hashAPIKeySecret(secret))) != 1 {
+		return nil, exception.APIKeyInvalid
+	}
+	// 所属用户已被删除
+	if key.User.UniCode == "" {
+		return nil, exception.APIKeyInvalid
+	}
+	if exc := s.userStatus.Check(ctx, &key.User); exc != nil {
+		return nil, exc
+	}
+	if key.RevokedAt != nil {
+		return nil, exception.APIKeyRevoked
+	}
+	now := time.Now()
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(now) {
+		return nil, exception.APIKeyExpired
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= s.config.LastUsedInterval {
+		err := s.repo.APIKey().UpdateByMap(ctx.Ctx, key.ID, map[string]any{
+			"last_used_at": now,
+			"last_used_ip": ctx.GetClientIP(),
+		})
+		if err != nil {
+			s.logger.Warn("update api key last used failed", zap.Uint64("id", key.ID), zap.Error(err))
+		}
+	}
+	return key, nil
+}
+
+func (s *APIKeyServiceImpl) create(ctx *ctx.Context, userID uint64, keyType enum.APIKeyType, params *dto.APIKeyCreateReqDto) (*dto.APIKeyCreateResDto, *exception.Exception) {
+	var expiresAt *time.Time
+	if params.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, params.ExpiresAt)
+		if err != nil || !t.After(time.Now()) {
+			return nil, exception.InvalidParam.Append("expiresAt must be in the future")
+		}
+		expiresAt = &t
+	}
+	if exc := validateScopes(params.Scopes); exc != nil {
+		return nil, exc
+	}
+	if s.config.MaxPerUser > 0 {
+		keys, err := s.repo.APIKey().GetMany(ctx.Ctx,
+			repo.Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()),
+		)
+		if err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		if len(keys) >= s.config.MaxPerUser {
+			return nil, exception.APIKeyLimitExceeded
+		}
+	}
+	keyID, secret, err := generateAPIKey()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	key := &model.APIKey{
+		UserID:     userID,
+		Name:       params.Name,
+		Type:       keyType,
+		KeyID:      keyID,
+		SecretHash: hashAPIKeySecret(secret),
+		Scopes:     normalizeScopes(params.Scopes),
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.repo.APIKey().Create(ctx.Ctx, key); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return &dto.APIKeyCreateResDto{
+		APIKeyResDto: *toAPIKeyResDto(key),
+		Key:          s.config.Prefix + "_" + keyID + "_" + secret,
+	}, nil
+}
+
+func (s *APIKeyServiceImpl) getUserKey(ctx *ctx.Context, userID uint64, keyID uint64) (*model.APIKey, *exception.Exception) {
+	key, err := s.repo.APIKey().GetOne(ctx.Ctx, repo.Where("id = ? AND user_id = ?", keyID, userID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if key == nil {
+		return nil, exception.APIKeyNotFound
+	}
+	return key, nil
+}
+
+// parseKey 拆分 <prefix>_<keyID>_<secret>, secret 本身可能包含下划线
+func (s *APIKeyServiceImpl) parseKey(rawKey string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(rawKey), s.config.Prefix+"_")
+	if !ok {
+		return "", "", false
+	}
+	keyID, secret, ok := strings.Cut(rest, "_")
+	if !ok || keyID == "" || secret == "" {
+		return "", "", false
+	}
+	return keyID, secret, true
+}
+
+func generateAPIKey() (string, string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(id), base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashAPIKeySecret secret 是高熵随机串, 使用 sha256 即可
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateScopes 权限范围只能是权限编码或 "*"
+func validateScopes(scopes []string) *exception.Exception {
+	for _, scope := range scopes {
+		if scope != APIKeyScopeAll && !enum.PermissionCode(scope).IsValid() {
+			return exception.InvalidParam.Append("unknown scope: " + scope)
+		}
+	}
+	return nil
+}
+
+func normalizeScopes(scopes []string) []string {
+	if len(scopes) == 0 {
+		return []string{APIKeyScopeAll}
+	}
+	seen := make(map[string]struct{}, len(scopes))
+	res := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		res = append(res, scope)
+	}
+	return res
+}
+
+func toAPIKeyResDto(key *model.APIKey) *dto.APIKeyResDto {
+	return &dto.APIKeyResDto{
+		ID:         key.ID,
+		Name:       key.Name,
+		Type:       key.Type.String(),
+		KeyID:      key.KeyID,
+		Scopes:     key.Scopes,
+		CreatedAt:  formatTime(key.CreatedAt),
+		ExpiresAt:  formatTime(key.ExpiresAt),
+		LastUsedAt: formatTime(key.LastUsedAt),
+		LastUsedIP: key.LastUsedIP,
+		RevokedAt:  formatTime(key.RevokedAt),
+	}
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"go-server-starter/internal/exception"
+	"testing"
+)
+
+func TestValidateScopes(t *testing.T) {
+	for _, scopes := range [][]string{nil, {"*"}, {"user:read", "org:write"}} {
+		if exc := validateScopes(scopes); exc != nil {
+			t.Fatalf("validateScopes(%v) = %v", scopes, exc)
+		}
+	}
+	for _, scopes := range [][]string{{"user:raed"}, {"user:read", "admin"}, {""}} {
+		if exc := validateScopes(scopes); exc == nil || exc.Code != exception.InvalidParam.Code {
+			t.Fatalf("validateScopes(%v) = %v, want InvalidParam", scopes, exc)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/asyn_queue"
+	"go-server-starter/pkg/utils"
+	"reflect"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// 审计操作
+const (
+	AuditActionUserUpdateInfo       = "user.update_info"
+	AuditActionUserRoleAssign       = "user.role.assign"
+	AuditActionUserRoleRevoke       = "user.role.revoke"
+	AuditActionUserStatusSet        = "user.status.set"
+	AuditActionRoleSetEnabled       = "role.set_enabled"
+	AuditActionImpersonationStart   = "impersonation.start"
+	AuditActionImpersonationEnd     = "impersonation.end"
+	AuditActionImpersonationRequest = "impersonation.request"
+	AuditActionUserDeletionRequest  = "user.deletion.request"
+	AuditActionUserDeletionCancel   = "user.deletion.cancel"
+	AuditActionUserTrash            = "user.trash"
+	AuditActionUserRestore          = "user.restore"
+)
+
+// AuditEntry 一条审计记录, Before/After 可以是任意可以 JSON 序列化的值, 只保存发生变化的字段
+type AuditEntry struct {
+	Action     string
+	TargetType enum.AuditTargetType
+	TargetID   string
+	Before     any
+	After      any
+}
+
+type AuditService interface {
+	Record(ctx *ctx.Context, entry AuditEntry)
+	HandleTask(ctx context.Context, task *asynq.Task) error
+	GetTable(ctx *ctx.Context, params dto.AuditLogTableQueryReqDto) (*dto.PaginationResDto[[]*dto.AuditLogResDto], *exception.Exception)
+}
+
+type AuditServiceImpl struct {
+	repo   repo.Repo
+	queue  *asyn_queue.Client
+	logger *zap.Logger
+}
+
+func NewAuditService(repo repo.Repo, queue *asyn_queue.Client, logger *zap.Logger) AuditService {
+	return &AuditServiceImpl{
+		repo:   repo,
+		queue:  queue,
+		logger: logger,
+	}
+}
+
+// Record 从请求上下文中补全操作人和设备信息, 通过异步队列写入, 入队失败时直接写库
+func (s *AuditServiceImpl) Record(ctx *ctx.Context, entry AuditEntry) {
+	now := time.Now()
+	before, after := auditDiff(entry.Before, entry.After)
+	log := &model.AuditLog{
+		Model:        model.Model{CreatedAt: &now},
+		ActorUniCode: ctx.Gtx.GetString(constant.CTX_KEY_OF_USER_UNI_CODE),
+		Impersonator: ctx.Gtx.GetString(constant.CTX_KEY_OF_IMPERSONATOR),
+		Action:       entry.Action,
+		TargetType:   entry.TargetType,
+		TargetID:     entry.TargetID,
+		Before:       before,
+		After:        after,
+		IP:           ctx.GetClientIP(),
+		DeviceType:   ctx.GetDeviceType().String(),
+		DeviceID:     ctx.GetDeviceID(),
+		UserAgent:    truncate(ctx.GetUserAgent(), 255),
+		RequestID:    ctx.GetRequestID(),
+	}
+	// 已显式记录的请求不再由中间件重复记录
+	ctx.Gtx.Set(constant.CTX_KEY_OF_AUDITED, true)
+
+	payload, err := json.Marshal(log)
+	if err == nil {
+		_, err = s.queue.Enqueue(ctx.Ctx, asynq.NewTask(constant.TASK_TYPE_OF_AUDIT_LOG, payload), asynq.MaxRetry(5))
+	}
+	if err == nil {
+		return
+	}
+	s.logger.Warn("enqueue audit log failed, writing directly", zap.String("action", log.Action), zap.Error(err))
+	if err := s.repo.AuditLog().Create(context.WithoutCancel(ctx.Ctx), log); err != nil {
+		s.logger.Error("write audit log failed", zap.Any("log", log), zap.Error(err))
+	}
+}
+
+// HandleTask 异步队列中写入审计日志
+func (s *AuditServiceImpl) HandleTask(ctx context.Context, task *asynq.Task) error {
+	var log model.AuditLog
+	if err := json.Unmarshal(task.Payload(), &log); err != nil {
+		return fmt.Errorf("unmarshal audit log: %v: %w", err, asynq.SkipRetry)
+	}
+	log.ID = 0
+	return s.repo.AuditLog().Create(ctx, &log)
+}
+
+func (s *AuditServiceImpl) GetTable(ctx *ctx.Context, params dto.AuditLogTableQueryReqDto) (*dto.PaginationResDto[[]*dto.AuditLogResDto], *exception.Exception) {
+	opts := []repo.QueryOption{
+		repo.Order("id DESC"),
+		repo.WherePtrNonEmpty("actor_uni_code = ?", params.ActorUniCode),
+		repo.WherePtrNonEmpty("impersonator = ?", params.Impersonator),
+		repo.WhereAutoLikePrefix("action", params.Action),
+		repo.WherePtrNonEmpty("target_type = ?", params.TargetType),
+		repo.WherePtrNonEmpty("target_id = ?", params.TargetID),
+		repo.WherePtrNonEmpty("request_id = ?", params.RequestID),
+	}
+	if params.From != "" {
+		from, _ := time.Parse(time.RFC3339, params.From)
+		opts = append(opts, repo.Where("created_at >= ?", from))
+	}
+	if params.To != "" {
+		to, _ := time.Parse(time.RFC3339, params.To)
+		opts = append(opts, repo.Where("created_at < ?", to))
+	}
+	logs, total, err := s.repo.AuditLog().GetTable(ctx.Ctx, params.Page, params.PageSize, opts...)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	res := make([]*dto.AuditLogResDto, len(logs))
+	for i, log := range logs {
+		res[i] = &dto.AuditLogResDto{
+			ID:           log.ID,
+			CreatedAt:    formatTime(log.CreatedAt),
+			ActorUniCode: log.ActorUniCode,
+			Impersonator: log.Impersonator,
+			Action:       log.Action,
+			TargetType:   log.TargetType.String(),
+			TargetID:     log.TargetID,
+			Before:       log.Before,
+			After:        log.After,
+			IP:           log.IP,
+			DeviceType:   log.DeviceType,
+			DeviceID:     log.DeviceID,
+			UserAgent:    log.UserAgent,
+			RequestID:    log.RequestID,
+		}
+	}
+	return utils.AssemblePaginationResDto(res, total, params.Page, params.PageSize), nil
+}
+
+// auditDiff 将前后的值转换为 map, 只保留发生变化的字段
+func auditDiff(before, after any) (map[string]any, map[string]any) {
+	b, a := toAuditMap(before), toAuditMap(after)
+	if b == nil || a == nil {
+		return b, a
+	}
+	for key, value := range b {
+		if other, ok := a[key]; ok && reflect.DeepEqual(value, other) {
+			delete(b, key)
+			delete(a, key)
+		}
+	}
+	return b, a
+}
+
+func toAuditMap(value any) map[string]any {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	var res map[string]any
+	if err := json.Unmarshal(data, &res); err != nil {
+		// 非对象的值统一放在 value 字段中
+		var raw any
+		_ = json.Unmarshal(data, &raw)
+		return map[string]any{"value": raw}
+	}
+	return res
+}
+
+// truncate 按字符截断, 与 varchar 的长度单位一致, 不会切开多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
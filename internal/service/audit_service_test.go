@@ -0,0 +1,30 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{s: "abc", n: 5, want: "abc"},
+		{s: "abcdef", n: 3, want: "abc"},
+		{s: "浏览器", n: 2, want: "浏览"},
+		{s: "a浏览器", n: 3, want: "a浏览"},
+		{s: "浏览器", n: 3, want: "浏览器"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+	got := truncate(strings.Repeat("浏", 300), 255)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != 255 {
+		t.Fatalf("truncate kept %d runes, valid %v", utf8.RuneCountInString(got), utf8.ValidString(got))
+	}
+}
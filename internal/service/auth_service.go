@@ -1,7 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
 	"go-server-starter/internal/ctx"
 	"go-server-starter/internal/dto"
 	"go-server-starter/internal/enum"
@@ -9,146 +12,634 @@ import (
 	"go-server-starter/internal/model"
 	"go-server-starter/internal/repo"
 	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/oauth"
+	"go-server-starter/pkg/password"
+	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/utils"
+	"slices"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type AuthService interface {
+	SendMobileCode(ctx *ctx.Context, params dto.AuthSendMobileCodeReqDto) *exception.Exception
+	SendEmailCode(ctx *ctx.Context, params dto.AuthSendEmailCodeReqDto) *exception.Exception
 	LoginByMobileAndCode(ctx *ctx.Context, params dto.AuthLoginByMobileAndCodeReqDto) (*dto.AuthTokenResDto, *exception.Exception)
 	LoginByEmailAndCode(ctx *ctx.Context, params dto.AuthLoginByEmailAndCodeReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	Register(ctx *ctx.Context, params dto.AuthRegisterReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	LoginByEmailAndPassword(ctx *ctx.Context, params dto.AuthLoginByEmailAndPasswordReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	LoginByMobileAndPassword(ctx *ctx.Context, params dto.AuthLoginByMobileAndPasswordReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	LoginByUsernameAndPassword(ctx *ctx.Context, params dto.AuthLoginByUsernameAndPasswordReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	ChangePassword(ctx *ctx.Context, params dto.AuthChangePasswordReqDto) *exception.Exception
+	SetPassword(ctx *ctx.Context, params dto.AuthSetPasswordReqDto) *exception.Exception
+	RefreshToken(ctx *ctx.Context, params dto.AuthRefreshTokenReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	Logout(ctx *ctx.Context) *exception.Exception
+	LogoutAll(ctx *ctx.Context) *exception.Exception
+	VerifyMFA(ctx *ctx.Context, params dto.AuthVerifyMFAReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	VerifyStepUp(ctx *ctx.Context, params dto.AuthVerifyStepUpReqDto) (*dto.AuthTokenResDto, *exception.Exception)
+	GetOAuthProviders(ctx *ctx.Context) []string
+	OAuthAuthorize(ctx *ctx.Context, provider string) (*dto.AuthOAuthAuthorizeResDto, *exception.Exception)
+	OAuthCallback(ctx *ctx.Context, provider string, params dto.AuthOAuthCallbackReqDto) (*dto.AuthTokenResDto, *exception.Exception)
 }
 
 type AuthServiceImpl struct {
-	repo   repo.Repo
-	jwt    *jwt.JWT
-	logger *zap.Logger
+	repo        repo.Repo
+	jwt         *jwt.JWT
+	redis       *redis.Redis
+	hasher      *password.Hasher
+	verifyCode  VerifyCodeService
+	session     UserSessionService
+	mfa         MFAService
+	userStatus  UserStatusService
+	loginEvent  LoginEventService
+	oauth       *oauth.Registry
+	oauthConfig *config.OAuthConfig
+	loginConfig *config.LoginConfig
+	logger      *zap.Logger
 }
 
-func NewAuthService(repo repo.Repo, jwt *jwt.JWT, logger *zap.Logger) AuthService {
+func NewAuthService(repo repo.Repo, jwt *jwt.JWT, redis *redis.Redis, hasher *password.Hasher, verifyCode VerifyCodeService, session UserSessionService, mfa MFAService, userStatus UserStatusService, loginEvent LoginEventService, oauth *oauth.Registry, oauthConfig *config.OAuthConfig, loginConfig *config.LoginConfig, logger *zap.Logger) AuthService {
 	return &AuthServiceImpl{
-		repo:   repo,
-		jwt:    jwt,
-		logger: logger,
+		repo:        repo,
+		jwt:         jwt,
+		redis:       redis,
+		hasher:      hasher,
+		verifyCode:  verifyCode,
+		session:     session,
+		mfa:         mfa,
+		userStatus:  userStatus,
+		loginEvent:  loginEvent,
+		oauth:       oauth,
+		oauthConfig: oauthConfig,
+		loginConfig: loginConfig,
+		logger:      logger,
 	}
 }
 
-func (s *AuthServiceImpl) LoginByMobileAndCode(ctx *ctx.Context, params dto.AuthLoginByMobileAndCodeReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
-	if params.Code != "666666" {
-		return nil, exception.UserMobileVerificationCodeIsIncorrect
+func (s *AuthServiceImpl) SendMobileCode(ctx *ctx.Context, params dto.AuthSendMobileCodeReqDto) *exception.Exception {
+	if params.Scene == "" {
+		params.Scene = enum.VerifyCodeSceneLogin
 	}
-	var deviceType = ctx.GetDeviceType()
 	params.Mobile = strings.ReplaceAll(params.Mobile, " ", "")
+	return s.verifyCode.Send(ctx, params.Scene, enum.VerifyCodeChannelMobile, MobileTarget(params.CountryCode, params.Mobile))
+}
+
+func (s *AuthServiceImpl) SendEmailCode(ctx *ctx.Context, params dto.AuthSendEmailCodeReqDto) *exception.Exception {
+	if params.Scene == "" {
+		params.Scene = enum.VerifyCodeSceneLogin
+	}
+	params.Email = strings.ToLower(strings.TrimSpace(params.Email))
+	return s.verifyCode.Send(ctx, params.Scene, enum.VerifyCodeChannelEmail, params.Email)
+}
+
+func (s *AuthServiceImpl) LoginByMobileAndCode(ctx *ctx.Context, params dto.AuthLoginByMobileAndCodeReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	params.Mobile = strings.ReplaceAll(params.Mobile, " ", "")
+	if exc := s.verifyCode.Verify(ctx, enum.VerifyCodeSceneLogin, enum.VerifyCodeChannelMobile, MobileTarget(params.CountryCode, params.Mobile), params.Code); exc != nil {
+		return nil, exc
+	}
 	user, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("mobile = ? AND country_code = ?", params.Mobile, params.CountryCode))
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, exception.InternalServerError.Append(err.Error())
 	}
 	if user == nil {
 		// 创建用户
-		tx := s.repo.DB().Begin()
-		userRepo := s.repo.User().WithTx(tx)
-		userRoleRepo := s.repo.UserRole().WithTx(tx)
-		defer func() {
-			if r := recover(); r != nil {
-				tx.Rollback()
-			}
-		}()
-		uniCode, err := userRepo.GenerateUniCode(ctx.Ctx)
-		if err != nil {
-			tx.Rollback()
-			return nil, exception.InternalServerError.Append(err.Error())
-		}
-		// 绑定角色
-		role, err := userRoleRepo.GetOne(ctx.Ctx, repo.Where("code = ?", enum.RoleCodeUser))
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			tx.Rollback()
-			return nil, exception.InternalServerError.Append(err.Error())
-		}
-		if role == nil {
-			tx.Rollback()
-			return nil, exception.UserRoleNotFound
-		}
 		user = &model.User{
-			UniCode:     uniCode,
 			Mobile:      params.Mobile,
 			CountryCode: params.CountryCode,
 			Nickname:    params.Mobile,
-			Roles:       []model.UserRole{*role},
 		}
-		err = userRepo.Create(ctx.Ctx, user)
-		if err != nil {
-			tx.Rollback()
-			return nil, exception.InternalServerError.Append(err.Error())
+		if exc := s.createUser(ctx, user); exc != nil {
+			return nil, exc
 		}
-		if err := tx.Commit().Error; err != nil {
-			return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return s.issueToken(ctx, user, enum.LoginMethodMobileCode)
+}
+
+func (s *AuthServiceImpl) LoginByEmailAndCode(ctx *ctx.Context, params dto.AuthLoginByEmailAndCodeReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	params.Email = strings.ToLower(strings.TrimSpace(params.Email))
+	if exc := s.verifyCode.Verify(ctx, enum.VerifyCodeSceneLogin, enum.VerifyCodeChannelEmail, params.Email, params.Code); exc != nil {
+		return nil, exc
+	}
+	user, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("email = ?", params.Email))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		// 创建用户
+		user = &model.User{
+			Email:    params.Email,
+			Nickname: params.Email,
+		}
+		if exc := s.createUser(ctx, user); exc != nil {
+			return nil, exc
 		}
 	}
-	token, err := s.jwt.GenerateToken(user.UniCode, deviceType)
+	return s.issueToken(ctx, user, enum.LoginMethodEmailCode)
+}
+
+func (s *AuthServiceImpl) Register(ctx *ctx.Context, params dto.AuthRegisterReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	exists, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("username = ?", params.Username))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if exists != nil {
+		return nil, exception.UserUsernameHasBeenRegistered
+	}
+	salt, err := s.hasher.GenerateSalt()
 	if err != nil {
 		return nil, exception.InternalServerError.Append(err.Error())
 	}
-	return &dto.AuthTokenResDto{
-		Token: token,
-	}, nil
+	hash, err := s.hasher.Hash(params.Password, salt)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if params.Nickname == "" {
+		params.Nickname = params.Username
+	}
+	user := &model.User{
+		Username: params.Username,
+		Nickname: params.Nickname,
+		Password: hash,
+		Salt:     salt,
+	}
+	if exc := s.createUser(ctx, user); exc != nil {
+		return nil, exc
+	}
+	return s.issueToken(ctx, user, enum.LoginMethodRegister)
 }
 
-func (s *AuthServiceImpl) LoginByEmailAndCode(ctx *ctx.Context, params dto.AuthLoginByEmailAndCodeReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
-	if params.Code != "666666" {
-		return nil, exception.UserEmailVerificationCodeIsIncorrect
-	}
-	var deviceType = ctx.GetDeviceType()
+func (s *AuthServiceImpl) LoginByEmailAndPassword(ctx *ctx.Context, params dto.AuthLoginByEmailAndPasswordReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
 	params.Email = strings.ToLower(strings.TrimSpace(params.Email))
-	user, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("email = ?", params.Email))
+	return s.loginByPassword(ctx, params.Password, repo.Where("email = ?", params.Email), enum.LoginMethodEmailPassword)
+}
+
+func (s *AuthServiceImpl) LoginByMobileAndPassword(ctx *ctx.Context, params dto.AuthLoginByMobileAndPasswordReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	params.Mobile = strings.ReplaceAll(params.Mobile, " ", "")
+	return s.loginByPassword(ctx, params.Password, repo.Where("mobile = ? AND country_code = ?", params.Mobile, params.CountryCode), enum.LoginMethodMobilePassword)
+}
+
+func (s *AuthServiceImpl) LoginByUsernameAndPassword(ctx *ctx.Context, params dto.AuthLoginByUsernameAndPasswordReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	return s.loginByPassword(ctx, params.Password, repo.Where("username = ?", params.Username), enum.LoginMethodUsernamePassword)
+}
+
+func (s *AuthServiceImpl) ChangePassword(ctx *ctx.Context, params dto.AuthChangePasswordReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	if user.Password == "" {
+		return exception.UserPasswordNotSet
+	}
+	if !s.hasher.Compare(params.OldPassword, user.Salt, user.Password) {
+		return exception.UserPasswordIsIncorrect
+	}
+	return s.savePassword(ctx, user, params.NewPassword)
+}
+
+func (s *AuthServiceImpl) SetPassword(ctx *ctx.Context, params dto.AuthSetPasswordReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	if user.Password != "" {
+		return exception.UserPasswordHasBeenSet
+	}
+	return s.savePassword(ctx, user, params.Password)
+}
+
+func (s *AuthServiceImpl) RefreshToken(ctx *ctx.Context, params dto.AuthRefreshTokenReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	pair, err := s.jwt.RotateRefreshToken(ctx.Ctx, params.RefreshToken)
+	if err != nil {
+		var blocked *jwt.UserBlockedError
+		switch {
+		case errors.Is(err, jwt.ErrRefreshTokenInvalid):
+			return nil, exception.TokenInvalid
+		case errors.Is(err, jwt.ErrRefreshTokenReused):
+			s.logger.Warn("refresh token reused, token family revoked", zap.String("ip", ctx.GetClientIP()))
+			return nil, exception.TokenHasBeenAttacked
+		case errors.Is(err, jwt.ErrRefreshTokenUsed):
+			return nil, exception.TokenUsed
+		case errors.Is(err, jwt.ErrRefreshTokenRevoked):
+			return nil, exception.TokenRevoked
+		case errors.As(err, &blocked):
+			return nil, exception.UserStatusException(blocked.Status)
+		default:
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+	}
+	if exc := s.session.Touch(ctx, pair); exc != nil {
+		s.logger.Warn("touch user session failed", zap.String("familyID", pair.FamilyID), zap.Strings("details", exc.Details))
+	}
+	return toAuthTokenResDto(pair), nil
+}
+
+// Logout 撤销当前访问令牌及其所属的令牌家族
+func (s *AuthServiceImpl) Logout(ctx *ctx.Context) *exception.Exception {
+	claims, ok := jwt.GetClaims(ctx.Gtx)
+	if !ok {
+		return exception.TokenNotFound
+	}
+	if err := s.jwt.RevokeToken(ctx.Ctx, claims); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.jwt.RevokeFamily(ctx.Ctx, claims.FamilyID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return s.session.RemoveByTokenID(ctx, claims.FamilyID)
+}
+
+// LogoutAll 使当前用户此前签发的所有令牌失效
+func (s *AuthServiceImpl) LogoutAll(ctx *ctx.Context) *exception.Exception {
+	uniCode, exc := ctx.GetUserUniCode()
+	if exc != nil {
+		return exc
+	}
+	if err := s.jwt.RevokeUserTokens(ctx.Ctx, uniCode); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return exc
+	}
+	return s.session.RemoveAllByUserID(ctx, userID)
+}
+
+// VerifyMFA 两步登录的第二步, 校验挑战令牌和两步验证码后签发令牌
+func (s *AuthServiceImpl) VerifyMFA(ctx *ctx.Context, params dto.AuthVerifyMFAReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	userID, exc := s.mfa.ConsumeChallenge(ctx, params.ChallengeToken, params.Code)
+	if exc != nil {
+		return nil, exc
+	}
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, exception.InternalServerError.Append(err.Error())
 	}
 	if user == nil {
-		// 创建用户
-		tx := s.repo.DB().Begin()
-		userRepo := s.repo.User().WithTx(tx)
-		userRoleRepo := s.repo.UserRole().WithTx(tx)
-		defer func() {
-			if r := recover(); r != nil {
-				tx.Rollback()
-			}
-		}()
-		uniCode, err := userRepo.GenerateUniCode(ctx.Ctx)
-		if err != nil {
-			tx.Rollback()
+		return nil, exception.UserNotFound
+	}
+	// 挑战期间账号状态可能已变更
+	if exc := s.userStatus.Check(ctx, user); exc != nil {
+		s.loginEvent.Record(ctx, user, enum.LoginMethodMfa, enum.LoginOutcomeFailure, exc.Message)
+		return nil, exc
+	}
+	return s.completeLogin(ctx, user, enum.LoginMethodMfa)
+}
+
+// VerifyStepUp 登录二次验证, 校验挑战令牌和发送到邮箱或手机的验证码后签发令牌
+func (s *AuthServiceImpl) VerifyStepUp(ctx *ctx.Context, params dto.AuthVerifyStepUpReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	key := constant.RedisKeyOfLoginStepUp(params.ChallengeToken)
+	data, err := s.redis.HGetAll(ctx.Ctx, key).Result()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if len(data) == 0 {
+		return nil, exception.LoginStepUpInvalid
+	}
+	if exc := s.verifyCode.Verify(ctx, enum.VerifyCodeSceneStepUp, enum.VerifyCodeChannel(data["channel"]), data["target"], params.Code); exc != nil {
+		return nil, exc
+	}
+	// 只有删除成功的请求才能完成登录
+	deleted, err := s.redis.Del(ctx.Ctx, key).Result()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if deleted == 0 {
+		return nil, exception.LoginStepUpInvalid
+	}
+	user, err := s.repo.User().GetByID(ctx.Ctx, utils.StrToUint64(data["userID"]))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	method := enum.LoginMethod(data["method"])
+	if exc := s.userStatus.Check(ctx, user); exc != nil {
+		s.loginEvent.Record(ctx, user, method, enum.LoginOutcomeFailure, exc.Message)
+		return nil, exc
+	}
+	return s.completeLogin(ctx, user, method)
+}
+
+func (s *AuthServiceImpl) GetOAuthProviders(ctx *ctx.Context) []string {
+	return s.oauth.Names()
+}
+
+// OAuthAuthorize 生成第三方登录授权地址, state, PKCE code_verifier 和 nonce 保存在 redis 中
+func (s *AuthServiceImpl) OAuthAuthorize(ctx *ctx.Context, provider string) (*dto.AuthOAuthAuthorizeResDto, *exception.Exception) {
+	p, err := s.oauth.Get(provider)
+	if err != nil {
+		return nil, exception.OAuthProviderNotFound
+	}
+	state, err := oauth.GenerateState()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	nonce, err := oauth.GenerateState()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	codeVerifier, err := oauth.GenerateCodeVerifier()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	authorizeURL, err := p.AuthCodeURL(ctx.Ctx, state, oauth.CodeChallengeS256(codeVerifier), nonce)
+	if err != nil {
+		s.logger.Error("build oauth authorize url failed", zap.String("provider", provider), zap.Error(err))
+		return nil, exception.OAuthExchangeFailed.Append(err.Error())
+	}
+	key := constant.RedisKeyOfOAuthState(state)
+	pipe := s.redis.TxPipeline()
+	pipe.HSet(ctx.Ctx, key, "provider", provider, "codeVerifier", codeVerifier, "nonce", nonce)
+	pipe.Expire(ctx.Ctx, key, s.oauthConfig.StateExpire)
+	if _, err := pipe.Exec(ctx.Ctx); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return &dto.AuthOAuthAuthorizeResDto{AuthorizeURL: authorizeURL, State: state}, nil
+}
+
+// OAuthCallback 校验 state 后换取第三方账号信息并登录, 没有关联账号时自动注册
+func (s *AuthServiceImpl) OAuthCallback(ctx *ctx.Context, provider string, params dto.AuthOAuthCallbackReqDto) (*dto.AuthTokenResDto, *exception.Exception) {
+	p, err := s.oauth.Get(provider)
+	if err != nil {
+		return nil, exception.OAuthProviderNotFound
+	}
+	// state 只能使用一次
+	key := constant.RedisKeyOfOAuthState(params.State)
+	data, err := s.redis.HGetAll(ctx.Ctx, key).Result()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	deleted, err := s.redis.Del(ctx.Ctx, key).Result()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if deleted == 0 || data["provider"] != provider {
+		return nil, exception.OAuthStateInvalid
+	}
+	info, err := p.Exchange(ctx.Ctx, params.Code, data["codeVerifier"], data["nonce"])
+	if err != nil {
+		s.logger.Warn("oauth exchange failed", zap.String("provider", provider), zap.Error(err))
+		return nil, exception.OAuthExchangeFailed
+	}
+	user, exc := s.findOrCreateOAuthUser(ctx, provider, info)
+	if exc != nil {
+		return nil, exc
+	}
+	return s.issueToken(ctx, user, enum.LoginMethodOauth)
+}
+
+// findOrCreateOAuthUser 按 provider + subject 查找用户, 邮箱已验证时关联同邮箱的用户, 否则自动注册
+func (s *AuthServiceImpl) findOrCreateOAuthUser(ctx *ctx.Context, provider string, info *oauth.UserInfo) (*model.User, *exception.Exception) {
+	now := time.Now()
+	identity, err := s.repo.UserIdentity().GetOne(ctx.Ctx, repo.Where("provider = ? AND subject = ?", provider, info.Subject))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if identity != nil {
+		user, err := s.repo.User().GetByID(ctx.Ctx, identity.UserID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, exception.InternalServerError.Append(err.Error())
 		}
-		// 绑定角色
-		role, err := userRoleRepo.GetOne(ctx.Ctx, repo.Where("code = ?", enum.RoleCodeUser))
+		if user == nil {
+			return nil, exception.UserNotFound
+		}
+		if err := s.repo.UserIdentity().UpdateByMap(ctx.Ctx, identity.ID, map[string]any{
+			"email":         info.Email,
+			"name":          info.Name,
+			"avatar_url":    info.AvatarURL,
+			"last_login_at": &now,
+		}); err != nil {
+			s.logger.Warn("update user identity failed", zap.Uint64("id", identity.ID), zap.Error(err))
+		}
+		return user, nil
+	}
+	identity = &model.UserIdentity{
+		Provider:    provider,
+		Subject:     info.Subject,
+		Email:       info.Email,
+		Name:        info.Name,
+		AvatarURL:   info.AvatarURL,
+		LastLoginAt: &now,
+	}
+	// 只有提供方确认过的邮箱才能关联已有用户, 否则可能被冒用
+	if info.EmailVerified && info.Email != "" {
+		user, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("email = ?", info.Email))
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			tx.Rollback()
 			return nil, exception.InternalServerError.Append(err.Error())
 		}
-		if role == nil {
-			tx.Rollback()
-			return nil, exception.UserRoleNotFound
+		if user != nil {
+			identity.UserID = user.ID
+			if err := s.repo.UserIdentity().Create(ctx.Ctx, identity); err != nil {
+				return nil, exception.InternalServerError.Append(err.Error())
+			}
+			return user, nil
 		}
-		user = &model.User{
-			UniCode:  uniCode,
-			Email:    params.Email,
-			Nickname: params.Email,
-			Roles:    []model.UserRole{*role},
+	}
+	// 创建用户
+	user := &model.User{
+		Nickname:   info.Name,
+		AvatarURL:  info.AvatarURL,
+		Identities: []model.UserIdentity{*identity},
+	}
+	if info.EmailVerified {
+		user.Email = info.Email
+	}
+	if user.Nickname == "" {
+		user.Nickname = info.Email
+	}
+	if user.Nickname == "" {
+		user.Nickname = provider + "_" + info.Subject
+	}
+	if exc := s.createUser(ctx, user); exc != nil {
+		return nil, exc
+	}
+	return user, nil
+}
+
+// loginByPassword 根据条件查询用户并校验密码, 用户不存在和密码错误返回相同的错误
+func (s *AuthServiceImpl) loginByPassword(ctx *ctx.Context, raw string, where repo.QueryOption, method enum.LoginMethod) (*dto.AuthTokenResDto, *exception.Exception) {
+	user, err := s.repo.User().GetOne(ctx.Ctx, where)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	matched := false
+	if user != nil && user.Password != "" {
+		matched = s.hasher.Compare(raw, user.Salt, user.Password)
+	} else {
+		// 没有可比较的密码时也计算一次哈希, 响应时间不会暴露账号是否存在
+		s.hasher.CompareDummy(raw)
+	}
+	if !matched {
+		if user != nil {
+			s.loginEvent.Record(ctx, user, method, enum.LoginOutcomeFailure, exception.UserPasswordIsIncorrect.Message)
 		}
-		err = userRepo.Create(ctx.Ctx, user)
+		return nil, exception.UserPasswordIsIncorrect
+	}
+	// 算法或成本变更后, 登录成功时重新哈希
+	if s.hasher.NeedsRehash(user.Password) {
+		if exc := s.savePassword(ctx, user, raw); exc != nil {
+			s.logger.Warn("rehash password failed", zap.String("uniCode", user.UniCode), zap.Strings("details", exc.Details))
+		}
+	}
+	return s.issueToken(ctx, user, method)
+}
+
+// savePassword 生成新的盐和哈希并保存
+func (s *AuthServiceImpl) savePassword(ctx *ctx.Context, user *model.User, raw string) *exception.Exception {
+	salt, err := s.hasher.GenerateSalt()
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	hash, err := s.hasher.Hash(raw, salt)
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, map[string]any{"password": hash, "salt": salt}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	user.Password = hash
+	user.Salt = salt
+	return nil
+}
+
+// createUser 创建用户并绑定默认角色
+func (s *AuthServiceImpl) createUser(ctx *ctx.Context, user *model.User) *exception.Exception {
+	err := s.repo.Tx().Do(ctx.Ctx, func(c context.Context) error {
+		uniCode, err := s.repo.User().GenerateUniCode(c)
 		if err != nil {
-			tx.Rollback()
-			return nil, exception.InternalServerError.Append(err.Error())
+			return err
 		}
-		if err := tx.Commit().Error; err != nil {
-			return nil, exception.InternalServerError.Append(err.Error())
+		// 绑定角色
+		role, err := s.repo.UserRole().GetOne(c, repo.Where("code = ?", enum.RoleCodeUser))
+		if err != nil {
+			return err
+		}
+		user.UniCode = uniCode
+		user.Roles = []model.UserRole{*role}
+		return s.repo.User().Create(c, user)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.UserRoleNotFound
+	}
+	// 先查询再创建无法防止并发注册, 以唯一索引为准
+	if exc := userDuplicateException(err); exc != nil {
+		return exc
+	}
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+// issueToken 第一步登录成功后签发令牌, 开启两步验证的用户返回挑战令牌, 需要二次验证的新登录返回二次验证挑战
+func (s *AuthServiceImpl) issueToken(ctx *ctx.Context, user *model.User, method enum.LoginMethod) (*dto.AuthTokenResDto, *exception.Exception) {
+	if exc := s.userStatus.Check(ctx, user); exc != nil {
+		s.loginEvent.Record(ctx, user, method, enum.LoginOutcomeFailure, exc.Message)
+		return nil, exc
+	}
+	enabled, exc := s.mfa.IsEnabled(ctx, user.ID)
+	if exc != nil {
+		return nil, exc
+	}
+	if enabled {
+		token, expiresAt, exc := s.mfa.CreateChallenge(ctx, user.ID)
+		if exc != nil {
+			return nil, exc
+		}
+		s.loginEvent.Record(ctx, user, method, enum.LoginOutcomeChallenge, "mfa")
+		return &dto.AuthTokenResDto{
+			MFARequired:        true,
+			ChallengeToken:     token,
+			ChallengeExpiresAt: expiresAt.Format(time.RFC3339),
+		}, nil
+	}
+	// 验证码登录本身已验证了邮箱或手机
+	if s.loginConfig.StepUp && slices.Contains(stepUpMethods, method) {
+		res, exc := s.stepUp(ctx, user, method)
+		if exc != nil || res != nil {
+			return res, exc
 		}
 	}
-	token, err := s.jwt.GenerateToken(user.UniCode, deviceType)
+	return s.completeLogin(ctx, user, method)
+}
+
+// 新登录需要二次验证的登录方式
+var stepUpMethods = []enum.LoginMethod{
+	enum.LoginMethodEmailPassword,
+	enum.LoginMethodMobilePassword,
+	enum.LoginMethodUsernamePassword,
+	enum.LoginMethodOauth,
+}
+
+// stepUp 来自新设备或新 IP 段的登录向邮箱或手机发送验证码并返回挑战, 不需要或无法二次验证时返回 nil
+func (s *AuthServiceImpl) stepUp(ctx *ctx.Context, user *model.User, method enum.LoginMethod) (*dto.AuthTokenResDto, *exception.Exception) {
+	newDevice, newIPRange, exc := s.loginEvent.Detect(ctx, user.ID)
+	if exc != nil {
+		return nil, exc
+	}
+	if !newDevice && !newIPRange {
+		return nil, nil
+	}
+	var channel enum.VerifyCodeChannel
+	var target string
+	switch {
+	case user.Email != "":
+		channel, target = enum.VerifyCodeChannelEmail, user.Email
+	case user.Mobile != "":
+		channel, target = enum.VerifyCodeChannelMobile, MobileTarget(user.CountryCode, user.Mobile)
+	default:
+		s.logger.Warn("login step-up skipped, no email or mobile", zap.String("uniCode", user.UniCode))
+		return nil, nil
+	}
+	if exc := s.verifyCode.Send(ctx, enum.VerifyCodeSceneStepUp, channel, target); exc != nil {
+		return nil, exc
+	}
+	token, err := utils.SecureRandomCode(32, utils.AlphaAll, utils.Digits)
 	if err != nil {
 		return nil, exception.InternalServerError.Append(err.Error())
 	}
+	key := constant.RedisKeyOfLoginStepUp(token)
+	pipe := s.redis.TxPipeline()
+	pipe.HSet(ctx.Ctx, key, "userID", user.ID, "method", method.String(), "channel", channel.String(), "target", target)
+	pipe.Expire(ctx.Ctx, key, s.loginConfig.StepUpExpire)
+	if _, err := pipe.Exec(ctx.Ctx); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	s.loginEvent.Record(ctx, user, method, enum.LoginOutcomeChallenge, "step_up")
 	return &dto.AuthTokenResDto{
-		Token: token,
+		StepUpRequired:     true,
+		StepUpChannel:      channel.String(),
+		ChallengeToken:     token,
+		ChallengeExpiresAt: time.Now().Add(s.loginConfig.StepUpExpire).Format(time.RFC3339),
 	}, nil
 }
+
+// completeLogin 签发令牌并记录会话和登录历史
+func (s *AuthServiceImpl) completeLogin(ctx *ctx.Context, user *model.User, method enum.LoginMethod) (*dto.AuthTokenResDto, *exception.Exception) {
+	pair, err := s.jwt.IssueTokenPair(ctx.Ctx, user.UniCode, ctx.GetDeviceType())
+	if err != nil {
+		return nil, exception.TokenGenerateFailed.Append(err.Error())
+	}
+	if exc := s.session.Create(ctx, user.ID, pair); exc != nil {
+		// 会话记录失败时作废刚签发的令牌, 避免出现无法管理的登录
+		if err := s.jwt.RevokeFamily(ctx.Ctx, pair.FamilyID); err != nil {
+			s.logger.Error("revoke token family failed", zap.String("familyID", pair.FamilyID), zap.Error(err))
+		}
+		return nil, exc
+	}
+	s.loginEvent.Record(ctx, user, method, enum.LoginOutcomeSuccess, "")
+	return toAuthTokenResDto(pair), nil
+}
+
+func toAuthTokenResDto(pair *jwt.TokenPair) *dto.AuthTokenResDto {
+	return &dto.AuthTokenResDto{
+		AccessToken:           pair.AccessToken,
+		AccessTokenExpiresAt:  pair.AccessTokenExpiresAt.Format(time.RFC3339),
+		RefreshToken:          pair.RefreshToken,
+		RefreshTokenExpiresAt: pair.RefreshTokenExpiresAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/oauth"
+	"go-server-starter/pkg/oauth/oauthtest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type stubMFAService struct{ MFAService }
+
+func (stubMFAService) IsEnabled(*ctx.Context, uint64) (bool, *exception.Exception) { return false, nil }
+
+type stubUserStatusService struct{ UserStatusService }
+
+func (stubUserStatusService) Check(*ctx.Context, *model.User) *exception.Exception { return nil }
+
+type stubUserSessionService struct{ UserSessionService }
+
+func (stubUserSessionService) Create(*ctx.Context, uint64, *jwt.TokenPair) *exception.Exception {
+	return nil
+}
+
+type stubLoginEventService struct{ LoginEventService }
+
+func (stubLoginEventService) Record(*ctx.Context, *model.User, enum.LoginMethod, enum.LoginOutcome, string) {
+}
+
+type oauthTestEnv struct {
+	service *AuthServiceImpl
+	server  *oauthtest.Server
+	db      *gorm.DB
+}
+
+// newOAuthTestEnv 使用进程内的 OIDC 提供方, SQLite 内存数据库和 miniredis 创建 AuthService
+func newOAuthTestEnv(t *testing.T) *oauthTestEnv {
+	t.Helper()
+	server := oauthtest.NewServer(t)
+	db := newTestDB(t, &model.UserRole{}, &model.User{}, &model.UserIdentity{})
+	if err := db.Create(&model.UserRole{Code: enum.RoleCodeUser, Enabled: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+	rds, _ := newTestRedis(t)
+	expires := config.MultiTokenExpireConfig{Default: time.Hour}
+	j, err := jwt.NewJWT(&config.JWTConfig{Issuer: "test", TokenSecret: "test-secret", AccessTokenExpires: expires, RefreshTokenExpires: expires}, rds, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := oauth.NewRegistry([]config.OAuthProviderConfig{server.OIDCConfig("mock"), server.OIDCConfig("other")}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewAuthService(repo.NewRepo(db, zap.NewNop()), j, rds, nil, nil, stubUserSessionService{}, stubMFAService{}, stubUserStatusService{}, stubLoginEventService{},
+		registry, &config.OAuthConfig{StateExpire: time.Minute}, &config.LoginConfig{}, zap.NewNop())
+	return &oauthTestEnv{service: s.(*AuthServiceImpl), server: server, db: db}
+}
+
+func newTestCtx() *ctx.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	return ctx.FromGinCtx(c)
+}
+
+// authorize 发起授权并模拟用户同意, 返回回调参数
+func (e *oauthTestEnv) authorize(t *testing.T, provider string) dto.AuthOAuthCallbackReqDto {
+	t.Helper()
+	res, exc := e.service.OAuthAuthorize(newTestCtx(), provider)
+	if exc != nil {
+		t.Fatalf("OAuthAuthorize: %v", exc)
+	}
+	code, state := e.server.Authorize(t, res.AuthorizeURL)
+	return dto.AuthOAuthCallbackReqDto{Code: code, State: state}
+}
+
+func (e *oauthTestEnv) login(t *testing.T, provider string) *dto.AuthTokenResDto {
+	t.Helper()
+	res, exc := e.service.OAuthCallback(newTestCtx(), provider, e.authorize(t, provider))
+	if exc != nil {
+		t.Fatalf("OAuthCallback: %+v", exc)
+	}
+	if res.AccessToken == "" || res.RefreshToken == "" {
+		t.Fatalf("tokens were not issued: %+v", res)
+	}
+	return res
+}
+
+func (e *oauthTestEnv) identity(t *testing.T) *model.UserIdentity {
+	t.Helper()
+	var identities []model.UserIdentity
+	if err := e.db.Where("provider = ?", "mock").Find(&identities).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 1 {
+		t.Fatalf("identities = %d, want 1", len(identities))
+	}
+	return &identities[0]
+}
+
+func (e *oauthTestEnv) countUsers(t *testing.T) int64 {
+	t.Helper()
+	var count int64
+	if err := e.db.Model(&model.User{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestOAuthCallbackCreatesUser(t *testing.T) {
+	env := newOAuthTestEnv(t)
+
+	env.login(t, "mock")
+	identity := env.identity(t)
+	var user model.User
+	if err := env.db.First(&user, identity.UserID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "user@example.com" || user.Nickname != "Test User" || identity.Subject != "subject-1" {
+		t.Fatalf("unexpected user %+v / identity %+v", user, identity)
+	}
+
+	// 再次登录使用已关联的用户
+	env.login(t, "mock")
+	if n := env.countUsers(t); n != 1 {
+		t.Fatalf("users = %d after second login, want 1", n)
+	}
+	if env.identity(t).UserID != user.ID {
+		t.Fatal("second login used another user")
+	}
+}
+
+func TestOAuthCallbackLinksVerifiedEmail(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	existing := &model.User{UniCode: "existing", Email: "user@example.com", Nickname: "existing"}
+	if err := env.db.Create(existing).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	env.login(t, "mock")
+	if env.identity(t).UserID != existing.ID {
+		t.Fatal("verified email was not linked to the existing user")
+	}
+	if n := env.countUsers(t); n != 1 {
+		t.Fatalf("users = %d, want 1", n)
+	}
+}
+
+func TestOAuthCallbackDoesNotLinkUnverifiedEmail(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	existing := &model.User{UniCode: "existing", Email: "user@example.com", Nickname: "existing"}
+	if err := env.db.Create(existing).Error; err != nil {
+		t.Fatal(err)
+	}
+	env.server.Claims["email_verified"] = false
+
+	env.login(t, "mock")
+	identity := env.identity(t)
+	if identity.UserID == existing.ID {
+		t.Fatal("unverified email was linked to the existing user")
+	}
+	var user model.User
+	if err := env.db.First(&user, identity.UserID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "" {
+		t.Fatalf("unverified email %q was saved on the new user", user.Email)
+	}
+}
+
+func TestOAuthCallbackRejectsStateMismatch(t *testing.T) {
+	env := newOAuthTestEnv(t)
+
+	params := env.authorize(t, "mock")
+	unknown := params
+	unknown.State = "unknown-state"
+	if _, exc := env.service.OAuthCallback(newTestCtx(), "mock", unknown); exc == nil || exc.Code != exception.OAuthStateInvalid.Code {
+		t.Fatalf("unknown state: %+v", exc)
+	}
+
+	// state 属于另一个提供方, 校验失败后也已作废
+	if _, exc := env.service.OAuthCallback(newTestCtx(), "other", params); exc == nil || exc.Code != exception.OAuthStateInvalid.Code {
+		t.Fatalf("state of another provider: %+v", exc)
+	}
+	if _, exc := env.service.OAuthCallback(newTestCtx(), "mock", params); exc == nil || exc.Code != exception.OAuthStateInvalid.Code {
+		t.Fatalf("consumed state: %+v", exc)
+	}
+	if n := env.countUsers(t); n != 0 {
+		t.Fatalf("users = %d, want 0", n)
+	}
+}
+
+func TestOAuthCallbackRejectsNonceMismatch(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	env.server.Nonce = "replayed-nonce"
+
+	if _, exc := env.service.OAuthCallback(newTestCtx(), "mock", env.authorize(t, "mock")); exc == nil || exc.Code != exception.OAuthExchangeFailed.Code {
+		t.Fatalf("nonce mismatch: %+v", exc)
+	}
+	if n := env.countUsers(t); n != 0 {
+		t.Fatalf("users = %d, want 0", n)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ImpersonationService interface {
+	Start(ctx *ctx.Context, userID uint64) (*dto.ImpersonationResDto, *exception.Exception)
+	End(ctx *ctx.Context) *exception.Exception
+	RecordRequest(ctx *ctx.Context)
+}
+
+type ImpersonationServiceImpl struct {
+	repo       repo.Repo
+	jwt        *jwt.JWT
+	userRole   UserRoleService
+	userStatus UserStatusService
+	audit      AuditService
+	logger     *zap.Logger
+}
+
+func NewImpersonationService(repo repo.Repo, jwt *jwt.JWT, userRole UserRoleService, userStatus UserStatusService, audit AuditService, logger *zap.Logger) ImpersonationService {
+	return &ImpersonationServiceImpl{
+		repo:       repo,
+		jwt:        jwt,
+		userRole:   userRole,
+		userStatus: userStatus,
+		audit:      audit,
+		logger:     logger,
+	}
+}
+
+// Start 为目标用户签发短期访问令牌, 不能模拟自己和超级管理员
+func (s *ImpersonationServiceImpl) Start(ctx *ctx.Context, userID uint64) (*dto.ImpersonationResDto, *exception.Exception) {
+	impersonator, exc := ctx.GetUserUniCode()
+	if exc != nil {
+		return nil, exc
+	}
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	if user.UniCode == impersonator {
+		return nil, exception.ImpersonationTargetInvalid
+	}
+	roles, exc := s.userRole.GetEffectiveRolesCodeByUniCode(ctx, user.UniCode)
+	if exc != nil {
+		return nil, exc
+	}
+	if slices.Contains(roles, enum.RoleCodeSuperAdmin) {
+		return nil, exception.ImpersonationTargetInvalid
+	}
+	if exc := s.userStatus.Check(ctx, user); exc != nil {
+		return nil, exc
+	}
+	token, expiresAt, err := s.jwt.GenerateImpersonationToken(user.UniCode, impersonator, ctx.GetDeviceType())
+	if err != nil {
+		return nil, exception.TokenGenerateFailed.Append(err.Error())
+	}
+	s.logger.Info("impersonation started",
+		zap.String("impersonator", impersonator),
+		zap.String("uniCode", user.UniCode),
+		zap.String("ip", ctx.GetClientIP()),
+		zap.Time("expiresAt", expiresAt),
+	)
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionImpersonationStart,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		After:      map[string]any{"uniCode": user.UniCode, "expiresAt": expiresAt.Format(time.RFC3339)},
+	})
+	return &dto.ImpersonationResDto{
+		AccessToken:          token,
+		AccessTokenExpiresAt: expiresAt.Format(time.RFC3339),
+		UniCode:              user.UniCode,
+	}, nil
+}
+
+// End 撤销当前的模拟登录令牌
+func (s *ImpersonationServiceImpl) End(ctx *ctx.Context) *exception.Exception {
+	claims, ok := jwt.GetClaims(ctx.Gtx)
+	if !ok {
+		return exception.TokenNotFound
+	}
+	if claims.Act == nil {
+		return exception.ImpersonationNotActive
+	}
+	if err := s.jwt.RevokeToken(ctx.Ctx, claims); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.jwt.RevokeFamily(ctx.Ctx, claims.FamilyID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	s.logger.Info("impersonation ended", zap.String("impersonator", claims.Act.UniCode), zap.String("uniCode", claims.UniCode))
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return exc
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionImpersonationEnd,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(userID, 10),
+	})
+	return nil
+}
+
+// RecordRequest 审计模拟登录期间的每一个请求, 在请求处理完成后调用
+func (s *ImpersonationServiceImpl) RecordRequest(ctx *ctx.Context) {
+	uniCode, exc := ctx.GetUserUniCode()
+	if exc != nil {
+		return
+	}
+	userID, err := s.repo.User().GetIDByUniCode(ctx.Ctx, uniCode)
+	if err != nil {
+		s.logger.Warn("get impersonated user failed", zap.String("uniCode", uniCode), zap.Error(err))
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionImpersonationRequest,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(userID, 10),
+		After: map[string]any{
+			"method": ctx.Gtx.Request.Method,
+			"path":   ctx.Gtx.Request.URL.Path,
+			"status": ctx.Gtx.Writer.Status(),
+		},
+	})
+}
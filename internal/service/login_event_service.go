@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/asyn_queue"
+	"go-server-starter/pkg/notifier"
+	"go-server-starter/pkg/utils"
+	"net/netip"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LoginEventService interface {
+	Detect(ctx *ctx.Context, userID uint64) (newDevice bool, newIPRange bool, exc *exception.Exception)
+	Record(ctx *ctx.Context, user *model.User, method enum.LoginMethod, outcome enum.LoginOutcome, reason string)
+	GetMyHistory(ctx *ctx.Context, params dto.PaginationReqDto) (*dto.PaginationResDto[[]*dto.LoginEventResDto], *exception.Exception)
+	HandleNotifyTask(ctx context.Context, task *asynq.Task) error
+}
+
+type LoginEventServiceImpl struct {
+	repo     repo.Repo
+	queue    *asyn_queue.Client
+	notifier notifier.Notifier
+	config   *config.LoginConfig
+	logger   *zap.Logger
+}
+
+func NewLoginEventService(repo repo.Repo, queue *asyn_queue.Client, notifier notifier.Notifier, config *config.LoginConfig, logger *zap.Logger) LoginEventService {
+	return &LoginEventServiceImpl{
+		repo:     repo,
+		queue:    queue,
+		notifier: notifier,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// Detect 与之前成功的登录比较, 判断是否来自新设备或新 IP 段, 首次登录不算
+func (s *LoginEventServiceImpl) Detect(ctx *ctx.Context, userID uint64) (bool, bool, *exception.Exception) {
+	success := repo.Where("user_id = ? AND outcome = ?", userID, enum.LoginOutcomeSuccess)
+	last, err := s.repo.LoginEvent().GetOne(ctx.Ctx, success)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, false, exception.InternalServerError.Append(err.Error())
+	}
+	if last == nil {
+		return false, false, nil
+	}
+	// 没有设备ID时按 User-Agent 区分设备
+	device := repo.Where("device_id = ? AND user_agent = ?", "", truncate(ctx.GetUserAgent(), 255))
+	if deviceID := ctx.GetDeviceID(); deviceID != "" {
+		device = repo.Where("device_id = ?", deviceID)
+	}
+	knownDevice, err := s.repo.LoginEvent().GetOne(ctx.Ctx, success, device)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, false, exception.InternalServerError.Append(err.Error())
+	}
+	knownRange, err := s.repo.LoginEvent().GetOne(ctx.Ctx, success, repo.Where("ip_range = ?", s.ipRange(ctx.GetClientIP())))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, false, exception.InternalServerError.Append(err.Error())
+	}
+	return knownDevice == nil, knownRange == nil, nil
+}
+
+// Record 记录一次登录, 成功的登录来自新设备或新 IP 段时通知用户, 记录失败不影响登录
+func (s *LoginEventServiceImpl) Record(ctx *ctx.Context, user *model.User, method enum.LoginMethod, outcome enum.LoginOutcome, reason string) {
+	event := &model.LoginEvent{
+		UserID:     user.ID,
+		Method:     method,
+		Outcome:    outcome,
+		Reason:     truncate(reason, 255),
+		IP:         ctx.GetClientIP(),
+		IPRange:    s.ipRange(ctx.GetClientIP()),
+		UserAgent:  truncate(ctx.GetUserAgent(), 255),
+		DeviceType: ctx.GetDeviceType(),
+		DeviceID:   ctx.GetDeviceID(),
+	}
+	if outcome == enum.LoginOutcomeSuccess {
+		newDevice, newIPRange, exc := s.Detect(ctx, user.ID)
+		if exc != nil {
+			s.logger.Warn("detect new login failed", zap.Uint64("userID", user.ID), zap.Strings("details", exc.Details))
+		}
+		event.NewDevice, event.NewIPRange = newDevice, newIPRange
+	}
+	if err := s.repo.LoginEvent().Create(ctx.Ctx, event); err != nil {
+		s.logger.Error("record login event failed", zap.Uint64("userID", user.ID), zap.Error(err))
+		return
+	}
+	if event.NewDevice || event.NewIPRange {
+		s.notify(ctx, user, event)
+	}
+}
+
+func (s *LoginEventServiceImpl) GetMyHistory(ctx *ctx.Context, params dto.PaginationReqDto) (*dto.PaginationResDto[[]*dto.LoginEventResDto], *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	events, total, err := s.repo.LoginEvent().GetTable(ctx.Ctx, params.Page, params.PageSize, repo.Where("user_id = ?", userID), repo.Order("id DESC"))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	res := make([]*dto.LoginEventResDto, len(events))
+	for i, event := range events {
+		res[i] = &dto.LoginEventResDto{
+			ID:         event.ID,
+			CreatedAt:  formatTime(event.CreatedAt),
+			Method:     event.Method.String(),
+			Outcome:    event.Outcome.String(),
+			Reason:     event.Reason,
+			IP:         event.IP,
+			UserAgent:  event.UserAgent,
+			DeviceType: event.DeviceType.String(),
+			DeviceID:   event.DeviceID,
+			NewDevice:  event.NewDevice,
+			NewIPRange: event.NewIPRange,
+		}
+	}
+	return utils.AssemblePaginationResDto(res, total, params.Page, params.PageSize), nil
+}
+
+// HandleNotifyTask 异步队列中发送新登录通知
+func (s *LoginEventServiceImpl) HandleNotifyTask(ctx context.Context, task *asynq.Task) error {
+	var event notifier.Event
+	if err := json.Unmarshal(task.Payload(), &event); err != nil {
+		return fmt.Errorf("unmarshal notification: %v: %w", err, asynq.SkipRetry)
+	}
+	return s.notifier.Notify(ctx, event)
+}
+
+// notify 通过异步队列发送通知, 入队失败时直接发送
+func (s *LoginEventServiceImpl) notify(ctx *ctx.Context, user *model.User, event *model.LoginEvent) {
+	device := event.DeviceType.String()
+	if event.UserAgent != "" {
+		device = event.UserAgent
+	}
+	n := notifier.Event{
+		Type:    notifier.EventTypeNewLogin,
+		UniCode: user.UniCode,
+		Email:   user.Email,
+		Locale:  ctx.GetLocale(),
+		Data: map[string]string{
+			"time":   time.Now().Format(time.RFC3339),
+			"ip":     event.IP,
+			"device": device,
+		},
+	}
+	if user.Mobile != "" {
+		n.Mobile = MobileTarget(user.CountryCode, user.Mobile)
+	}
+	payload, err := json.Marshal(n)
+	if err == nil {
+		_, err = s.queue.Enqueue(ctx.Ctx, asynq.NewTask(constant.TASK_TYPE_OF_LOGIN_NOTIFY, payload), asynq.MaxRetry(3))
+	}
+	if err == nil {
+		return
+	}
+	if err := s.notifier.Notify(ctx.Ctx, n); err != nil {
+		s.logger.Error("send new login notification failed", zap.String("uniCode", user.UniCode), zap.Error(err))
+	}
+}
+
+// ipRange IP 所在的网段, 无法解析时返回原值
+func (s *LoginEventServiceImpl) ipRange(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+	addr = addr.Unmap()
+	bits := s.config.IPv6PrefixLen
+	if addr.Is4() {
+		bits = s.config.IPv4PrefixLen
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ip
+	}
+	return prefix.String()
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/totp"
+	"go-server-starter/pkg/utils"
+	"slices"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MFAService interface {
+	GetStatus(ctx *ctx.Context) (*dto.MFAStatusResDto, *exception.Exception)
+	EnrollTOTP(ctx *ctx.Context) (*dto.MFATOTPEnrollResDto, *exception.Exception)
+	ConfirmTOTP(ctx *ctx.Context, params dto.MFACodeReqDto) (*dto.MFARecoveryCodesResDto, *exception.Exception)
+	DisableTOTP(ctx *ctx.Context, params dto.MFACodeReqDto) *exception.Exception
+	RegenerateRecoveryCodes(ctx *ctx.Context, params dto.MFACodeReqDto) (*dto.MFARecoveryCodesResDto, *exception.Exception)
+	IsEnabled(ctx *ctx.Context, userID uint64) (bool, *exception.Exception)
+	CreateChallenge(ctx *ctx.Context, userID uint64) (string, time.Time, *exception.Exception)
+	ConsumeChallenge(ctx *ctx.Context, challengeToken string, code string) (uint64, *exception.Exception)
+	CheckRequired(ctx *ctx.Context, uniCode string, roles []enum.RoleCode) *exception.Exception
+}
+
+type MFAServiceImpl struct {
+	repo   repo.Repo
+	redis  *redis.Redis
+	config *config.MFAConfig
+	logger *zap.Logger
+}
+
+func NewMFAService(repo repo.Repo, redis *redis.Redis, config *config.MFAConfig, logger *zap.Logger) MFAService {
+	return &MFAServiceImpl{
+		repo:   repo,
+		redis:  redis,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (s *MFAServiceImpl) GetStatus(ctx *ctx.Context) (*dto.MFAStatusResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	enabled, exc := s.IsEnabled(ctx, user.ID)
+	if exc != nil {
+		return nil, exc
+	}
+	res := &dto.MFAStatusResDto{Enabled: enabled, Required: s.isRequired(user.Roles)}
+	if enabled {
+		codes, err := s.repo.UserRecoveryCode().GetMany(ctx.Ctx, repo.Where("user_id = ? AND used_at IS NULL", user.ID))
+		if err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		res.RecoveryCodesRemaining = len(codes)
+	}
+	return res, nil
+}
+
+// EnrollTOTP 生成新的密钥, 确认前不生效, 重复调用会覆盖未确认的密钥
+func (s *MFAServiceImpl) EnrollTOTP(ctx *ctx.Context) (*dto.MFATOTPEnrollResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	existing, exc := s.getTOTP(ctx, user.ID)
+	if exc != nil {
+		return nil, exc
+	}
+	if existing != nil && existing.Enabled {
+		return nil, exception.MFAAlreadyEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if existing != nil {
+		err = s.repo.UserTOTP().UpdateByMap(ctx.Ctx, existing.ID, map[string]any{"secret": secret, "last_used_step": 0})
+	} else {
+		err = s.repo.UserTOTP().Create(ctx.Ctx, &model.UserTOTP{UserID: user.ID, Secret: secret})
+	}
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return &dto.MFATOTPEnrollResDto{
+		Secret: secret,
+		URI:    totp.URI(s.config.Issuer, accountName(user), secret),
+	}, nil
+}
+
+// ConfirmTOTP 使用认证器 App 生成的验证码确认开启, 并返回恢复码
+func (s *MFAServiceImpl) ConfirmTOTP(ctx *ctx.Context, params dto.MFACodeReqDto) (*dto.MFARecoveryCodesResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	record, exc := s.getTOTP(ctx, user.ID)
+	if exc != nil {
+		return nil, exc
+	}
+	if record == nil {
+		return nil, exception.MFANotEnrolled
+	}
+	if record.Enabled {
+		return nil, exception.MFAAlreadyEnabled
+	}
+	step, ok := totp.Validate(record.Secret, params.Code, time.Now(), s.config.Skew)
+	if !ok {
+		return nil, exception.MFACodeIsIncorrect
+	}
+	codes, hashes, err := s.generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	err = s.repo.Tx().Do(ctx.Ctx, func(c context.Context) error {
+		now := time.Now()
+		if err := s.repo.UserTOTP().UpdateByMap(c, record.ID, map[string]any{
+			"enabled":        true,
+			"confirmed_at":   &now,
+			"last_used_step": step,
+		}); err != nil {
+			return err
+		}
+		if err := s.repo.UserRecoveryCode().DeleteByUserID(c, user.ID); err != nil {
+			return err
+		}
+		s.repo.Tx().AfterCommit(c, func(context.Context) { s.clearEnabledCache(ctx, user.UniCode) })
+		return s.repo.UserRecoveryCode().CreateBatch(c, hashes)
+	})
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return &dto.MFARecoveryCodesResDto{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP 关闭两步验证, 角色要求开启两步验证时不允许关闭
+func (s *MFAServiceImpl) DisableTOTP(ctx *ctx.Context, params dto.MFACodeReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	if s.isRequired(user.Roles) {
+		return exception.MFARequired
+	}
+	record, exc := s.getTOTP(ctx, user.ID)
+	if exc != nil {
+		return exc
+	}
+	if exc := s.verify(ctx, user.ID, params.Code); exc != nil {
+		return exc
+	}
+	err := s.repo.Tx().Do(ctx.Ctx, func(c context.Context) error {
+		if err := s.repo.UserTOTP().HardDelete(c, record.ID); err != nil {
+			return err
+		}
+		s.repo.Tx().AfterCommit(c, func(context.Context) { s.clearEnabledCache(ctx, user.UniCode) })
+		return s.repo.UserRecoveryCode().DeleteByUserID(c, user.ID)
+	})
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码, 旧的恢复码全部失效
+func (s *MFAServiceImpl) RegenerateRecoveryCodes(ctx *ctx.Context, params dto.MFACodeReqDto) (*dto.MFARecoveryCodesResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	if exc := s.verify(ctx, user.ID, params.Code); exc != nil {
+		return nil, exc
+	}
+	codes, hashes, err := s.generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	err = s.repo.Tx().Do(ctx.Ctx, func(c context.Context) error {
+		if err := s.repo.UserRecoveryCode().DeleteByUserID(c, user.ID); err != nil {
+			return err
+		}
+		return s.repo.UserRecoveryCode().CreateBatch(c, hashes)
+	})
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return &dto.MFARecoveryCodesResDto{RecoveryCodes: codes}, nil
+}
+
+func (s *MFAServiceImpl) IsEnabled(ctx *ctx.Context, userID uint64) (bool, *exception.Exception) {
+	record, exc := s.getTOTP(ctx, userID)
+	if exc != nil {
+		return false, exc
+	}
+	return record != nil && record.Enabled, nil
+}
+
+// CreateChallenge 第一步登录成功后创建挑战, 返回挑战令牌
+func (s *MFAServiceImpl) CreateChallenge(ctx *ctx.Context, userID uint64) (string, time.Time, *exception.Exception) {
+	token, err := utils.SecureRandomCode(32, utils.AlphaAll, utils.Digits)
+	if err != nil {
+		return "", time.Time{}, exception.InternalServerError.Append(err.Error())
+	}
+	key := constant.RedisKeyOfMFAChallenge(token)
+	pipe := s.redis.TxPipeline()
+	pipe.HSet(ctx.Ctx, key, "userID", userID, "attempts", 0)
+	pipe.Expire(ctx.Ctx, key, s.config.ChallengeExpire)
+	if _, err := pipe.Exec(ctx.Ctx); err != nil {
+		s.logger.Error("save mfa challenge failed", zap.Uint64("userID", userID), zap.Error(err))
+		return "", time.Time{}, exception.InternalServerError.Append(err.Error())
+	}
+	return token, time.Now().Add(s.config.ChallengeExpire), nil
+}
+
+// ConsumeChallenge 校验挑战和两步验证码, 成功后挑战失效, 返回用户ID
+func (s *MFAServiceImpl) ConsumeChallenge(ctx *ctx.Context, challengeToken string, code string) (uint64, *exception.Exception) {
+	key := constant.RedisKeyOfMFAChallenge(challengeToken)
+	data, err := s.redis.HGetAll(ctx.Ctx, key).Result()
+	if err != nil {
+		return 0, exception.InternalServerError.Append(err.Error())
+	}
+	if len(data) == 0 {
+		return 0, exception.MFAChallengeInvalid
+	}
+	attempts, err := s.redis.HIncrBy(ctx.Ctx, key, "attempts", 1).Result()
+	if err != nil {
+		return 0, exception.InternalServerError.Append(err.Error())
+	}
+	if attempts > int64(s.config.MaxAttempts) {
+		s.redis.Del(ctx.Ctx, key)
+		return 0, exception.MFAChallengeTooManyAttempts
+	}
+	userID := utils.StrToUint64(data["userID"])
+	if exc := s.verify(ctx, userID, code); exc != nil {
+		return 0, exc
+	}
+	// 只有删除成功的请求才能完成登录
+	deleted, err := s.redis.Del(ctx.Ctx, key).Result()
+	if err != nil {
+		return 0, exception.InternalServerError.Append(err.Error())
+	}
+	if deleted == 0 {
+		return 0, exception.MFAChallengeInvalid
+	}
+	return userID, nil
+}
+
+// CheckRequired 用户角色要求两步验证但尚未开启时返回错误
+func (s *MFAServiceImpl) CheckRequired(ctx *ctx.Context, uniCode string, roles []enum.RoleCode) *exception.Exception {
+	if !slices.ContainsFunc(roles, func(role enum.RoleCode) bool {
+		return slices.Contains(s.config.RequiredRoles, role)
+	}) {
+		return nil
+	}
+	key := constant.RedisKeyOfMFAEnabled(uniCode)
+	cached, err := s.redis.Get(ctx.Ctx, key).Result()
+	if err != nil && err != goredis.Nil {
+		s.logger.Error("get cached mfa enabled failed", zap.String("uniCode", uniCode), zap.Error(err))
+		return exception.InternalServerError.Append(err.Error())
+	}
+	enabled := cached == "1"
+	if err == goredis.Nil {
+		userID, err := s.repo.User().GetIDByUniCode(ctx.Ctx, uniCode)
+		if err != nil {
+			return exception.InternalServerError.Append(err.Error())
+		}
+		var exc *exception.Exception
+		if enabled, exc = s.IsEnabled(ctx, userID); exc != nil {
+			return exc
+		}
+		value := "0"
+		if enabled {
+			value = "1"
+		}
+		if err := s.redis.Set(ctx.Ctx, key, value, constant.REDIS_EXPIRE_OF_MFA_ENABLED).Err(); err != nil {
+			s.logger.Warn("set cached mfa enabled failed", zap.String("uniCode", uniCode), zap.Error(err))
+		}
+	}
+	if !enabled {
+		return exception.MFARequired
+	}
+	return nil
+}
+
+// verify 校验 TOTP 验证码或恢复码
+func (s *MFAServiceImpl) verify(ctx *ctx.Context, userID uint64, code string) *exception.Exception {
+	record, exc := s.getTOTP(ctx, userID)
+	if exc != nil {
+		return exc
+	}
+	if record == nil || !record.Enabled {
+		return exception.MFANotEnabled
+	}
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(record.Secret, code, time.Now(), s.config.Skew)
+		if !ok {
+			return exception.MFACodeIsIncorrect
+		}
+		// 同一时间步的验证码只能使用一次
+		used, err := s.repo.UserTOTP().UseStep(ctx.Ctx, record.ID, step)
+		if err != nil {
+			return exception.InternalServerError.Append(err.Error())
+		}
+		if !used {
+			return exception.MFACodeIsIncorrect
+		}
+		return nil
+	}
+	used, err := s.repo.UserRecoveryCode().Use(ctx.Ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if !used {
+		return exception.MFACodeIsIncorrect
+	}
+	s.logger.Info("recovery code used", zap.Uint64("userID", userID))
+	return nil
+}
+
+func (s *MFAServiceImpl) getTOTP(ctx *ctx.Context, userID uint64) (*model.UserTOTP, *exception.Exception) {
+	record, err := s.repo.UserTOTP().GetOne(ctx.Ctx, repo.Where("user_id = ?", userID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return record, nil
+}
+
+func (s *MFAServiceImpl) isRequired(roles []model.UserRole) bool {
+	for _, role := range roles {
+		if slices.Contains(s.config.RequiredRoles, role.Code) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MFAServiceImpl) clearEnabledCache(ctx *ctx.Context, uniCode string) {
+	if err := s.redis.Del(ctx.Ctx, constant.RedisKeyOfMFAEnabled(uniCode)).Err(); err != nil {
+		s.logger.Warn("clear cached mfa enabled failed", zap.String("uniCode", uniCode), zap.Error(err))
+	}
+}
+
+// generateRecoveryCodes 生成恢复码, 返回明文和待保存的哈希
+func (s *MFAServiceImpl) generateRecoveryCodes(userID uint64) ([]string, []*model.UserRecoveryCode, error) {
+	codes := make([]string, s.config.RecoveryCodeCount)
+	records := make([]*model.UserRecoveryCode, s.config.RecoveryCodeCount)
+	for i := range codes {
+		raw, err := utils.SecureRandomCode(10, utils.AlphaLower, utils.Digits)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		records[i] = &model.UserRecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(codes[i])}
+	}
+	return codes, records, nil
+}
+
+// hashRecoveryCode 恢复码是高熵随机串, 使用 sha256 即可
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// accountName 认证器 App 中显示的账号
+func accountName(user *model.User) string {
+	switch {
+	case user.Email != "":
+		return user.Email
+	case user.Username != "":
+		return user.Username
+	case user.Mobile != "":
+		return user.CountryCode + user.Mobile
+	default:
+		return user.UniCode
+	}
+}
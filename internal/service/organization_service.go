@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OrganizationService interface {
+	Create(ctx *ctx.Context, params dto.OrganizationCreateReqDto) (*dto.OrganizationResDto, *exception.Exception)
+	GetMine(ctx *ctx.Context) ([]*dto.OrganizationResDto, *exception.Exception)
+	Resolve(ctx *ctx.Context, slug string, uniCode string) (*model.Organization, *model.OrganizationMember, *exception.Exception)
+	GetCurrent(ctx *ctx.Context) (*dto.OrganizationResDto, *exception.Exception)
+	IssueToken(ctx *ctx.Context) (*dto.OrganizationTokenResDto, *exception.Exception)
+	GetMembers(ctx *ctx.Context) ([]*dto.OrganizationMemberResDto, *exception.Exception)
+	AddMember(ctx *ctx.Context, params dto.OrganizationAddMemberReqDto) *exception.Exception
+	UpdateMember(ctx *ctx.Context, userID uint64, params dto.OrganizationUpdateMemberReqDto) *exception.Exception
+	RemoveMember(ctx *ctx.Context, userID uint64) *exception.Exception
+}
+
+type OrganizationServiceImpl struct {
+	repo   repo.Repo
+	jwt    *jwt.JWT
+	logger *zap.Logger
+}
+
+func NewOrganizationService(repo repo.Repo, jwt *jwt.JWT, logger *zap.Logger) OrganizationService {
+	return &OrganizationServiceImpl{
+		repo:   repo,
+		jwt:    jwt,
+		logger: logger,
+	}
+}
+
+// Create 创建组织, 创建者成为所有者
+func (s *OrganizationServiceImpl) Create(ctx *ctx.Context, params dto.OrganizationCreateReqDto) (*dto.OrganizationResDto, *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	// 回收站中的组织仍占用 slug, 唯一索引包含软删除的行
+	exists, err := s.repo.Organization().GetOne(ctx.Ctx, repo.Unscoped(), repo.Where("slug = ?", params.Slug))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if exists != nil {
+		return nil, exception.OrganizationSlugTaken
+	}
+	org := &model.Organization{Name: params.Name, Slug: params.Slug, OwnerID: userID}
+	err = s.repo.Tx().Do(ctx.Ctx, func(c context.Context) error {
+		if err := s.repo.Organization().Create(c, org); err != nil {
+			return err
+		}
+		member := &model.OrganizationMember{UserID: userID, Role: enum.OrganizationRoleOwner}
+		return s.repo.OrganizationMember().Create(repo.WithTenant(c, org.ID), member)
+	})
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return toOrganizationResDto(org, enum.OrganizationRoleOwner), nil
+}
+
+// GetMine 当前用户加入的所有组织, 需要跨租户查询
+func (s *OrganizationServiceImpl) GetMine(ctx *ctx.Context) ([]*dto.OrganizationResDto, *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	members, err := s.repo.OrganizationMember().GetMany(repo.WithoutTenant(ctx.Ctx), repo.Where("user_id = ?", userID))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	res := make([]*dto.OrganizationResDto, 0, len(members))
+	if len(members) == 0 {
+		return res, nil
+	}
+	roles := make(map[uint64]enum.OrganizationRole, len(members))
+	ids := make([]uint64, len(members))
+	for i, member := range members {
+		roles[member.TenantID] = member.Role
+		ids[i] = member.TenantID
+	}
+	orgs, err := s.repo.Organization().GetByIDs(ctx.Ctx, ids, repo.Order("id ASC"))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	for _, org := range orgs {
+		res = append(res, toOrganizationResDto(org, roles[org.ID]))
+	}
+	return res, nil
+}
+
+// Resolve 根据 Slug 查找组织, 并确认用户是该组织的成员
+func (s *OrganizationServiceImpl) Resolve(ctx *ctx.Context, slug string, uniCode string) (*model.Organization, *model.OrganizationMember, *exception.Exception) {
+	org, err := s.repo.Organization().GetOne(ctx.Ctx, repo.Where("slug = ?", slug))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, exception.InternalServerError.Append(err.Error())
+	}
+	if org == nil {
+		return nil, nil, exception.OrganizationNotFound
+	}
+	userID, err := s.repo.User().GetIDByUniCode(ctx.Ctx, uniCode)
+	if err != nil {
+		return nil, nil, exception.InternalServerError.Append(err.Error())
+	}
+	member, err := s.repo.OrganizationMember().GetOne(repo.WithTenant(ctx.Ctx, org.ID), repo.Where("user_id = ?", userID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, exception.InternalServerError.Append(err.Error())
+	}
+	if member == nil {
+		return nil, nil, exception.OrganizationNotMember
+	}
+	return org, member, nil
+}
+
+func (s *OrganizationServiceImpl) GetCurrent(ctx *ctx.Context) (*dto.OrganizationResDto, *exception.Exception) {
+	tenantID, ok := repo.TenantFromContext(ctx.Ctx)
+	if !ok {
+		return nil, exception.OrganizationTenantRequired
+	}
+	org, err := s.repo.Organization().GetByID(ctx.Ctx, tenantID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if org == nil {
+		return nil, exception.OrganizationNotFound
+	}
+	member, exc := s.currentMember(ctx)
+	if exc != nil {
+		return nil, exc
+	}
+	return toOrganizationResDto(org, member.Role), nil
+}
+
+// IssueToken 为当前组织签发绑定的访问令牌, 之后请求头和子域名不能再切换到其他组织
+func (s *OrganizationServiceImpl) IssueToken(ctx *ctx.Context) (*dto.OrganizationTokenResDto, *exception.Exception) {
+	claims, ok := jwt.GetClaims(ctx.Gtx)
+	if !ok {
+		return nil, exception.TokenNotFound
+	}
+	tenantID, ok := repo.TenantFromContext(ctx.Ctx)
+	if !ok {
+		return nil, exception.OrganizationTenantRequired
+	}
+	org, err := s.repo.Organization().GetByID(ctx.Ctx, tenantID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if org == nil {
+		return nil, exception.OrganizationNotFound
+	}
+	token, expiresAt, err := s.jwt.GenerateTenantToken(claims.UniCode, claims.FamilyID, claims.DeviceType, org.Slug)
+	if err != nil {
+		return nil, exception.TokenGenerateFailed.Append(err.Error())
+	}
+	return &dto.OrganizationTokenResDto{
+		AccessToken:          token,
+		AccessTokenExpiresAt: expiresAt.Format(time.RFC3339),
+		Slug:                 org.Slug,
+	}, nil
+}
+
+func (s *OrganizationServiceImpl) GetMembers(ctx *ctx.Context) ([]*dto.OrganizationMemberResDto, *exception.Exception) {
+	members, err := s.repo.OrganizationMember().GetMany(ctx.Ctx, repo.Preload("User"), repo.Order("id ASC"))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	res := make([]*dto.OrganizationMemberResDto, len(members))
+	for i, member := range members {
+		res[i] = toOrganizationMemberResDto(member)
+	}
+	return res, nil
+}
+
+// AddMember 管理员可以添加成员, 只有所有者可以添加管理员和所有者
+func (s *OrganizationServiceImpl) AddMember(ctx *ctx.Context, params dto.OrganizationAddMemberReqDto) *exception.Exception {
+	role := enum.OrganizationRole(params.Role)
+	if exc := s.checkCanManage(ctx, role); exc != nil {
+		return exc
+	}
+	user, err := s.repo.User().GetByID(ctx.Ctx, params.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return exception.UserNotFound
+	}
+	exists, err := s.repo.OrganizationMember().GetOne(ctx.Ctx, repo.Where("user_id = ?", user.ID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if exists != nil {
+		return exception.OrganizationMemberExists
+	}
+	if err := s.repo.OrganizationMember().Create(ctx.Ctx, &model.OrganizationMember{UserID: user.ID, Role: role}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) UpdateMember(ctx *ctx.Context, userID uint64, params dto.OrganizationUpdateMemberReqDto) *exception.Exception {
+	member, exc := s.getMember(ctx, userID)
+	if exc != nil {
+		return exc
+	}
+	role := enum.OrganizationRole(params.Role)
+	if exc := s.checkCanManage(ctx, member.Role); exc != nil {
+		return exc
+	}
+	if exc := s.checkCanManage(ctx, role); exc != nil {
+		return exc
+	}
+	if member.Role == enum.OrganizationRoleOwner && role != enum.OrganizationRoleOwner {
+		if exc := s.checkNotLastOwner(ctx); exc != nil {
+			return exc
+		}
+	}
+	if err := s.repo.OrganizationMember().UpdateByMap(ctx.Ctx, member.ID, map[string]any{"role": role}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+// RemoveMember 移除成员, 组织至少保留一个所有者
+func (s *OrganizationServiceImpl) RemoveMember(ctx *ctx.Context, userID uint64) *exception.Exception {
+	member, exc := s.getMember(ctx, userID)
+	if exc != nil {
+		return exc
+	}
+	if exc := s.checkCanManage(ctx, member.Role); exc != nil {
+		return exc
+	}
+	if member.Role == enum.OrganizationRoleOwner {
+		if exc := s.checkNotLastOwner(ctx); exc != nil {
+			return exc
+		}
+	}
+	// 成员关系有唯一索引, 硬删除以便重新加入
+	if err := s.repo.OrganizationMember().HardDelete(ctx.Ctx, member.ID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) currentMember(ctx *ctx.Context) (*model.OrganizationMember, *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	member, err := s.repo.OrganizationMember().GetOne(ctx.Ctx, repo.Where("user_id = ?", userID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if member == nil {
+		return nil, exception.OrganizationNotMember
+	}
+	return member, nil
+}
+
+func (s *OrganizationServiceImpl) getMember(ctx *ctx.Context, userID uint64) (*model.OrganizationMember, *exception.Exception) {
+	member, err := s.repo.OrganizationMember().GetOne(ctx.Ctx, repo.Where("user_id = ?", userID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if member == nil {
+		return nil, exception.OrganizationMemberNotFound
+	}
+	return member, nil
+}
+
+// checkCanManage 所有者和管理员只能由所有者管理
+func (s *OrganizationServiceImpl) checkCanManage(ctx *ctx.Context, role enum.OrganizationRole) *exception.Exception {
+	if role == enum.OrganizationRoleMember {
+		return nil
+	}
+	operator, exc := s.currentMember(ctx)
+	if exc != nil {
+		return exc
+	}
+	if operator.Role != enum.OrganizationRoleOwner {
+		return exception.OrganizationRoleForbidden
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) checkNotLastOwner(ctx *ctx.Context) *exception.Exception {
+	owners, err := s.repo.OrganizationMember().GetMany(ctx.Ctx, repo.Where("role = ?", enum.OrganizationRoleOwner))
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if len(owners) <= 1 {
+		return exception.OrganizationLastOwner
+	}
+	return nil
+}
+
+func toOrganizationResDto(org *model.Organization, role enum.OrganizationRole) *dto.OrganizationResDto {
+	return &dto.OrganizationResDto{
+		ID:   org.ID,
+		Name: org.Name,
+		Slug: org.Slug,
+		Role: role.String(),
+	}
+}
+
+func toOrganizationMemberResDto(member *model.OrganizationMember) *dto.OrganizationMemberResDto {
+	return &dto.OrganizationMemberResDto{
+		UserID:   member.UserID,
+		UniCode:  member.User.UniCode,
+		Nickname: member.User.Nickname,
+		Role:     member.Role.String(),
+		JoinedAt: formatTime(member.CreatedAt),
+	}
+}
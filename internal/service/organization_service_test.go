@@ -0,0 +1,32 @@
+package service
+
+import (
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestOrganizationMemberUniquePerTenant(t *testing.T) {
+	db := newTestDB(t, &model.User{}, &model.OrganizationMember{})
+	if !db.Migrator().HasIndex(&model.OrganizationMember{}, "idx_org_member") {
+		t.Fatal("missing idx_org_member")
+	}
+	r := repo.NewRepo(db, zap.NewNop())
+	c := newTestCtx()
+	create := func(tenantID uint64) error {
+		member := &model.OrganizationMember{UserID: 1, Role: enum.OrganizationRoleMember}
+		return r.OrganizationMember().Create(repo.WithTenant(c.Ctx, tenantID), member)
+	}
+	if err := create(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := create(2); err != nil {
+		t.Fatalf("same user in another organization: %v", err)
+	}
+	if err := create(1); err == nil {
+		t.Fatal("duplicate member in the same organization was created")
+	}
+}
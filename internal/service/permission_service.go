@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/json"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type PermissionService interface {
+	GetPermissionsCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.PermissionCode, *exception.Exception)
+	GetCachedPermissionsCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.PermissionCode, *exception.Exception)
+}
+
+type PermissionServiceImpl struct {
+	repo     repo.Repo
+	redis    *redis.Redis
+	userRole UserRoleService
+	logger   *zap.Logger
+}
+
+func NewPermissionService(repo repo.Repo, redis *redis.Redis, userRole UserRoleService, logger *zap.Logger) PermissionService {
+	return &PermissionServiceImpl{
+		repo:     repo,
+		redis:    redis,
+		userRole: userRole,
+		logger:   logger,
+	}
+}
+
+// GetPermissionsCodeByUniCode 用户的有效权限, 即有效角色 (按继承展开并去掉禁用的角色) 的权限并集
+func (s *PermissionServiceImpl) GetPermissionsCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.PermissionCode, *exception.Exception) {
+	codes, exc := s.userRole.GetEffectiveRolesCodeByUniCode(ctx, uniCode)
+	if exc != nil {
+		return nil, exc
+	}
+	permissions := make([]enum.PermissionCode, 0)
+	if len(codes) == 0 {
+		return permissions, nil
+	}
+	// 结果会缓存, 从主库读取
+	roles, err := s.repo.UserRole().GetMany(repo.WithPrimary(ctx.Ctx), repo.Where("code IN ? AND enabled = ?", codes, true), repo.Preload("Permissions"))
+	if err != nil {
+		s.logger.Error("get permissions code by uni code failed", zap.String("uniCode", uniCode), zap.Error(err))
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	seen := make(map[enum.PermissionCode]struct{})
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			if _, ok := seen[permission.Code]; ok {
+				continue
+			}
+			seen[permission.Code] = struct{}{}
+			permissions = append(permissions, permission.Code)
+		}
+	}
+	return permissions, nil
+}
+
+func (s *PermissionServiceImpl) GetCachedPermissionsCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.PermissionCode, *exception.Exception) {
+	key := constant.RedisKeyOfAuthPermissions(uniCode)
+	dataStr, err := s.redis.Get(ctx.Ctx, key).Result()
+	if err != nil && err != goredis.Nil {
+		s.logger.Error("get cached permissions code by uni code failed", zap.String("uniCode", uniCode), zap.Error(err))
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if err == nil {
+		var permissions []enum.PermissionCode
+		if err := json.Unmarshal([]byte(dataStr), &permissions); err != nil {
+			s.logger.Error("unmarshal permissions code by uni code failed", zap.String("uniCode", uniCode), zap.Error(err))
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		return permissions, nil
+	}
+	// 缓存不存在时从数据库获取并缓存
+	permissions, exc := s.GetPermissionsCodeByUniCode(ctx, uniCode)
+	if exc != nil {
+		return nil, exc
+	}
+	permissionsJSON, err := json.Marshal(permissions)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.redis.Set(ctx.Ctx, key, permissionsJSON, constant.REDIS_EXPIRE_OF_AUTH_PERMISSIONS).Err(); err != nil {
+		s.logger.Error("set cached permissions code by uni code failed", zap.String("uniCode", uniCode), zap.Error(err))
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return permissions, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestPermissionsIncludeInheritedRoles(t *testing.T) {
+	db := newTestDB(t, &model.Permission{}, &model.UserRole{}, &model.User{})
+	rds, _ := newTestRedis(t)
+	r := repo.NewRepo(db, zap.NewNop())
+	roleConfig := &config.RoleConfig{Inherits: map[enum.RoleCode][]enum.RoleCode{
+		enum.RoleCodeUserSvip: {enum.RoleCodeUserVip},
+		enum.RoleCodeUserVip:  {enum.RoleCodeUser},
+	}}
+	userRole := NewUserRoleService(r, rds, nil, roleConfig, zap.NewNop())
+	s := NewPermissionService(r, rds, userRole, zap.NewNop())
+
+	grants := map[enum.RoleCode]enum.PermissionCode{
+		enum.RoleCodeUser:     enum.PermissionCodeUserRead,
+		enum.RoleCodeUserVip:  enum.PermissionCodeUserWrite,
+		enum.RoleCodeUserSvip: enum.PermissionCodeSessionManage,
+	}
+	roles := map[enum.RoleCode]*model.UserRole{}
+	for code, permission := range grants {
+		role := &model.UserRole{Code: code, Enabled: true, Permissions: []model.Permission{{Code: permission}}}
+		if err := db.Create(role).Error; err != nil {
+			t.Fatal(err)
+		}
+		roles[code] = role
+	}
+	user := &model.User{UniCode: "svip", Roles: []model.UserRole{*roles[enum.RoleCodeUserSvip]}}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	permissions, exc := s.GetCachedPermissionsCodeByUniCode(newTestCtx(), user.UniCode)
+	if exc != nil {
+		t.Fatal(exc)
+	}
+	for _, want := range grants {
+		if !slices.Contains(permissions, want) {
+			t.Fatalf("permissions %v missing inherited %s", permissions, want)
+		}
+	}
+
+	// 继承链中被禁用的角色的权限不生效
+	if err := db.Model(roles[enum.RoleCodeUserVip]).Update("enabled", false).Error; err != nil {
+		t.Fatal(err)
+	}
+	if exc := userRole.ClearCache(newTestCtx(), user.UniCode); exc != nil {
+		t.Fatal(exc)
+	}
+	permissions, exc = s.GetCachedPermissionsCodeByUniCode(newTestCtx(), user.UniCode)
+	if exc != nil {
+		t.Fatal(exc)
+	}
+	if slices.Contains(permissions, enum.PermissionCodeUserWrite) || !slices.Contains(permissions, enum.PermissionCodeUserRead) {
+		t.Fatalf("permissions after disabling user_vip = %v", permissions)
+	}
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/asyn_queue"
+	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/redis"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PersonalDataHook 模块注册的个人数据导出和清除方法, Export 的返回值需要可以 JSON 序列化
+// Erase 在同一个事务中执行, 使用 ctx 访问仓库即可加入事务, 需要可以重复执行
+type PersonalDataHook struct {
+	Name   string
+	Export func(ctx context.Context, user *model.User) (any, error)
+	Erase  func(ctx context.Context, user *model.User) error
+}
+
+// PersonalDataService 个人数据导出和账号注销, 注销有冷静期, 到期后由后台任务按模块清除数据
+type PersonalDataService interface {
+	Register(hook PersonalDataHook)
+	Export(ctx *ctx.Context) (*dto.PersonalDataExportResDto, *exception.Exception)
+	ExportArchive(ctx *ctx.Context) ([]byte, *exception.Exception)
+	GetDeletion(ctx *ctx.Context) (*dto.AccountDeletionResDto, *exception.Exception)
+	RequestDeletion(ctx *ctx.Context, params dto.AccountDeletionReqDto) (*dto.AccountDeletionResDto, *exception.Exception)
+	CancelDeletion(ctx *ctx.Context) *exception.Exception
+	HandleEraseTask(ctx context.Context, task *asynq.Task) error
+	Purge(ctx context.Context, user *model.User) error
+}
+
+type PersonalDataServiceImpl struct {
+	repo        repo.Repo
+	jwt         *jwt.JWT
+	redis       *redis.Redis
+	queue       *asyn_queue.Client
+	userAccount UserAccountService
+	audit       AuditService
+	config      *config.PrivacyConfig
+	logger      *zap.Logger
+	hooks       []PersonalDataHook
+}
+
+type accountErasePayload struct {
+	UserID uint64 `json:"userId"`
+}
+
+func NewPersonalDataService(repo repo.Repo, jwt *jwt.JWT, redis *redis.Redis, queue *asyn_queue.Client, userAccount UserAccountService, audit AuditService, config *config.PrivacyConfig, logger *zap.Logger) PersonalDataService {
+	s := &PersonalDataServiceImpl{
+		repo:        repo,
+		jwt:         jwt,
+		redis:       redis,
+		queue:       queue,
+		userAccount: userAccount,
+		audit:       audit,
+		config:      config,
+		logger:      logger,
+	}
+	s.registerBuiltin()
+	return s
+}
+
+// Register 注册模块的导出和清除方法, 按注册顺序执行, 名称重复时覆盖
+func (s *PersonalDataServiceImpl) Register(hook PersonalDataHook) {
+	for i, h := range s.hooks {
+		if h.Name == hook.Name {
+			s.hooks[i] = hook
+			return
+		}
+	}
+	s.hooks = append(s.hooks, hook)
+}
+
+// Export 导出当前用户的全部个人数据
+func (s *PersonalDataServiceImpl) Export(ctx *ctx.Context) (*dto.PersonalDataExportResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	c := repo.WithoutTenant(ctx.Ctx)
+	data := make(map[string]any, len(s.hooks))
+	for _, hook := range s.hooks {
+		if hook.Export == nil {
+			continue
+		}
+		value, err := hook.Export(c, user)
+		if err != nil {
+			return nil, exception.InternalServerError.Append(fmt.Sprintf("export %s: %v", hook.Name, err))
+		}
+		data[hook.Name] = value
+	}
+	return &dto.PersonalDataExportResDto{
+		UniCode:    user.UniCode,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Data:       data,
+	}, nil
+}
+
+// ExportArchive 导出为 zip, 每个模块一个 <name>.json
+func (s *PersonalDataServiceImpl) ExportArchive(ctx *ctx.Context) ([]byte, *exception.Exception) {
+	export, exc := s.Export(ctx)
+	if exc != nil {
+		return nil, exc
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	write := func(name string, value any) error {
+		f, err := w.Create(name + ".json")
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+	meta := map[string]string{"uniCode": export.UniCode, "exportedAt": export.ExportedAt}
+	if err := write("export", meta); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	for _, hook := range s.hooks {
+		value, ok := export.Data[hook.Name]
+		if !ok {
+			continue
+		}
+		if err := write(hook.Name, value); err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *PersonalDataServiceImpl) GetDeletion(ctx *ctx.Context) (*dto.AccountDeletionResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	return toAccountDeletionResDto(user), nil
+}
+
+// RequestDeletion 申请注销, 冷静期结束后清除数据, 已申请时返回原来的计划
+func (s *PersonalDataServiceImpl) RequestDeletion(ctx *ctx.Context, params dto.AccountDeletionReqDto) (*dto.AccountDeletionResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	if exc := s.userAccount.ConsumeReauth(ctx, user, params.ReauthToken); exc != nil {
+		return nil, exc
+	}
+	if user.DeletionScheduledAt != nil {
+		return toAccountDeletionResDto(user), nil
+	}
+	now := time.Now()
+	scheduledAt := now.Add(s.config.DeletionGracePeriod)
+	// 先入队再写库, 写库失败时任务执行时发现没有申请会直接跳过
+	payload, err := json.Marshal(accountErasePayload{UserID: user.ID})
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	task := asynq.NewTask(constant.TASK_TYPE_OF_ACCOUNT_ERASE, payload)
+	if _, err := s.queue.Enqueue(ctx.Ctx, task, asynq.ProcessAt(scheduledAt), asynq.MaxRetry(10)); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	updates := map[string]any{
+		"deletion_requested_at": now,
+		"deletion_scheduled_at": scheduledAt,
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, updates); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	before := toAccountDeletionResDto(user)
+	user.DeletionRequestedAt, user.DeletionScheduledAt = &now, &scheduledAt
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserDeletionRequest,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     before,
+		After:      toAccountDeletionResDto(user),
+	})
+	return toAccountDeletionResDto(user), nil
+}
+
+// CancelDeletion 冷静期内撤销注销, 已入队的任务执行时会直接跳过
+func (s *PersonalDataServiceImpl) CancelDeletion(ctx *ctx.Context) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	if user.DeletionScheduledAt == nil {
+		return exception.UserDeletionNotRequested
+	}
+	updates := map[string]any{
+		"deletion_requested_at": nil,
+		"deletion_scheduled_at": nil,
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, updates); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	before := toAccountDeletionResDto(user)
+	user.DeletionRequestedAt, user.DeletionScheduledAt = nil, nil
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserDeletionCancel,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     before,
+		After:      toAccountDeletionResDto(user),
+	})
+	return nil
+}
+
+// HandleEraseTask 冷静期结束后清除账号数据, 已撤销或重新申请 (计划时间更晚) 时跳过
+func (s *PersonalDataServiceImpl) HandleEraseTask(ctx context.Context, task *asynq.Task) error {
+	var payload accountErasePayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("unmarshal account erase: %v: %w", err, asynq.SkipRetry)
+	}
+	user, err := s.repo.User().GetByID(ctx, payload.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if user == nil || user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(time.Now()) {
+		return nil
+	}
+	return s.erase(ctx, user, s.config.EraseMode == "delete")
+}
+
+// Purge 彻底删除账号和各模块的数据, 不受 EraseMode 影响, 用于清理回收站
+func (s *PersonalDataServiceImpl) Purge(ctx context.Context, user *model.User) error {
+	return s.erase(ctx, user, true)
+}
+
+// erase 撤销令牌, 在一个事务中依次执行各模块的清除方法, 最后匿名化或彻底删除账号
+func (s *PersonalDataServiceImpl) erase(ctx context.Context, user *model.User, hardDelete bool) error {
+	ctx = repo.WithoutTenant(ctx)
+	if err := s.jwt.RevokeUserTokens(ctx, user.UniCode); err != nil {
+		return err
+	}
+	return s.repo.Tx().Do(ctx, func(ctx context.Context) error {
+		for _, hook := range s.hooks {
+			if hook.Erase == nil {
+				continue
+			}
+			if err := hook.Erase(ctx, user); err != nil {
+				return fmt.Errorf("erase %s: %w", hook.Name, err)
+			}
+		}
+		if err := s.repo.UserRole().RevokeAllFromUser(ctx, user.ID); err != nil {
+			return err
+		}
+		if hardDelete {
+			if err := s.repo.User().HardDelete(ctx, user.ID); err != nil {
+				return err
+			}
+		} else {
+			// 保留账号行和 uniCode, 其他记录中的引用不会失效
+			updates := map[string]any{
+				"username":      "",
+				"email":         "",
+				"mobile":        "",
+				"country_code":  "",
+				"desc":          "",
+				"password":      "",
+				"salt":          "",
+				"nickname":      "",
+				"avatar_url":    "",
+				"status":        enum.UserStatusDeactivated,
+				"status_reason": "account deleted",
+				"status_until":  nil,
+				"status_set_by": nil,
+				"status_set_at": time.Now(),
+			}
+			if err := s.repo.User().UpdateByMap(ctx, user.ID, updates); err != nil {
+				return err
+			}
+		}
+		s.repo.Tx().AfterCommit(ctx, func(ctx context.Context) {
+			keys := []string{
+				constant.RedisKeyOfAuthRoles(user.UniCode),
+				constant.RedisKeyOfAuthEffectiveRoles(user.UniCode),
+				constant.RedisKeyOfAuthPermissions(user.UniCode),
+			}
+			if err := s.redis.Del(ctx, keys...).Err(); err != nil {
+				s.logger.Warn("clear erased account cache failed", zap.Uint64("userID", user.ID), zap.Error(err))
+			}
+			s.logger.Info("account erased", zap.Uint64("userID", user.ID), zap.Bool("hardDelete", hardDelete))
+		})
+		return nil
+	})
+}
+
+// registerBuiltin 注册账号自身的数据, 其他模块通过 Register 追加
+func (s *PersonalDataServiceImpl) registerBuiltin() {
+	s.Register(PersonalDataHook{
+		Name: "profile",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			return map[string]any{
+				"uniCode":             user.UniCode,
+				"username":            user.Username,
+				"email":               user.Email,
+				"mobile":              user.Mobile,
+				"countryCode":         user.CountryCode,
+				"nickname":            user.Nickname,
+				"avatarURL":           user.AvatarURL,
+				"desc":                user.Desc,
+				"status":              user.Status,
+				"createdAt":           formatTime(user.CreatedAt),
+				"deletionRequestedAt": formatTime(user.DeletionRequestedAt),
+				"deletionScheduledAt": formatTime(user.DeletionScheduledAt),
+			}, nil
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "roles",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			u, err := s.repo.User().GetByID(ctx, user.ID, repo.Preload("Roles"))
+			if err != nil {
+				return nil, err
+			}
+			return roleCodes(u.Roles), nil
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "sessions",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			return s.repo.UserSession().GetMany(ctx, repo.Where("user_id = ?", user.ID))
+		},
+		Erase: func(ctx context.Context, user *model.User) error {
+			return hardDeleteByUserID[model.UserSession](ctx, s.repo.UserSession(), user.ID)
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "login_history",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			return s.repo.LoginEvent().GetMany(ctx, repo.Where("user_id = ?", user.ID), repo.Order("id DESC"))
+		},
+		Erase: func(ctx context.Context, user *model.User) error {
+			return hardDeleteByUserID[model.LoginEvent](ctx, s.repo.LoginEvent(), user.ID)
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "identities",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			return s.repo.UserIdentity().GetMany(ctx, repo.Where("user_id = ?", user.ID))
+		},
+		Erase: func(ctx context.Context, user *model.User) error {
+			return hardDeleteByUserID[model.UserIdentity](ctx, s.repo.UserIdentity(), user.ID)
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "api_keys",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			return s.repo.APIKey().GetMany(ctx, repo.Where("user_id = ?", user.ID))
+		},
+		Erase: func(ctx context.Context, user *model.User) error {
+			return hardDeleteByUserID[model.APIKey](ctx, s.repo.APIKey(), user.ID)
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "mfa",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			totp, err := s.repo.UserTOTP().GetOne(ctx, repo.Where("user_id = ?", user.ID))
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			codes, err := s.repo.UserRecoveryCode().GetMany(ctx, repo.Where("user_id = ?", user.ID))
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"totp": totp, "recoveryCodes": codes}, nil
+		},
+		Erase: func(ctx context.Context, user *model.User) error {
+			if err := hardDeleteByUserID[model.UserTOTP](ctx, s.repo.UserTOTP(), user.ID); err != nil {
+				return err
+			}
+			return hardDeleteByUserID[model.UserRecoveryCode](ctx, s.repo.UserRecoveryCode(), user.ID)
+		},
+	})
+	s.Register(PersonalDataHook{
+		Name: "organizations",
+		Export: func(ctx context.Context, user *model.User) (any, error) {
+			return s.repo.OrganizationMember().GetMany(ctx, repo.Where("user_id = ?", user.ID))
+		},
+		Erase: func(ctx context.Context, user *model.User) error {
+			return hardDeleteByUserID[model.OrganizationMember](ctx, s.repo.OrganizationMember(), user.ID)
+		},
+	})
+}
+
+// hardDeleteByUserID 彻底删除用户在一张表中的所有行, 包括已软删除的
+func hardDeleteByUserID[T any, PT interface {
+	*T
+	GetID() uint64
+}](ctx context.Context, r repo.BaseRepo[T], userID uint64) error {
+	rows, err := r.GetMany(ctx, repo.Unscoped(), repo.Where("user_id = ?", userID))
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	ids := make([]uint64, len(rows))
+	for i, row := range rows {
+		ids[i] = PT(row).GetID()
+	}
+	return r.HardDeleteByIDs(ctx, ids)
+}
+
+func toAccountDeletionResDto(user *model.User) *dto.AccountDeletionResDto {
+	return &dto.AccountDeletionResDto{
+		Requested:   user.DeletionScheduledAt != nil,
+		RequestedAt: formatTime(user.DeletionRequestedAt),
+		ScheduledAt: formatTime(user.DeletionScheduledAt),
+	}
+}
@@ -3,8 +3,13 @@ package service
 import (
 	"go-server-starter/internal/config"
 	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/asyn_queue"
 	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/notifier"
+	"go-server-starter/pkg/oauth"
+	"go-server-starter/pkg/password"
 	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/sender"
 	"go-server-starter/pkg/snowflake"
 
 	"go.uber.org/zap"
@@ -15,31 +20,79 @@ type Service interface {
 	User() UserService
 	UserRole() UserRoleService
 	Auth() AuthService
+	VerifyCode() VerifyCodeService
+	UserSession() UserSessionService
+	MFA() MFAService
+	APIKey() APIKeyService
+	UserAccount() UserAccountService
+	Permission() PermissionService
+	UserStatus() UserStatusService
+	Impersonation() ImpersonationService
+	Organization() OrganizationService
+	Audit() AuditService
+	LoginEvent() LoginEventService
+	PersonalData() PersonalDataService
+	Trash() TrashService
 }
 
 type ServiceImpl struct {
-	db              *gorm.DB
-	config          *config.Config
-	jwt             *jwt.JWT
-	redis           *redis.Redis
-	snowflake       *snowflake.Snowflake
-	logger          *zap.Logger
-	userService     UserService
-	userRoleService UserRoleService
-	authService     AuthService
-}
-
-func NewService(db *gorm.DB, config *config.Config, jwt *jwt.JWT, redis *redis.Redis, snowflake *snowflake.Snowflake, repo repo.Repo, logger *zap.Logger) Service {
+	db                   *gorm.DB
+	config               *config.Config
+	jwt                  *jwt.JWT
+	redis                *redis.Redis
+	snowflake            *snowflake.Snowflake
+	logger               *zap.Logger
+	userService          UserService
+	userRoleService      UserRoleService
+	authService          AuthService
+	verifyCodeService    VerifyCodeService
+	userSessionService   UserSessionService
+	mfaService           MFAService
+	apiKeyService        APIKeyService
+	userAccountService   UserAccountService
+	permissionService    PermissionService
+	userStatusService    UserStatusService
+	impersonationService ImpersonationService
+	organizationService  OrganizationService
+	auditService         AuditService
+	loginEventService    LoginEventService
+	personalDataService  PersonalDataService
+	trashService         TrashService
+}
+
+func NewService(db *gorm.DB, config *config.Config, jwt *jwt.JWT, redis *redis.Redis, snowflake *snowflake.Snowflake, sender sender.Sender, hasher *password.Hasher, oauth *oauth.Registry, notifier notifier.Notifier, queue *asyn_queue.Client, repo repo.Repo, logger *zap.Logger) Service {
+	auditService := NewAuditService(repo, queue, logger)
+	loginEventService := NewLoginEventService(repo, queue, notifier, &config.Login, logger)
+	verifyCodeService := NewVerifyCodeService(&config.VerifyCode, redis, sender, logger)
+	userSessionService := NewUserSessionService(repo, jwt, &config.Session, logger)
+	mfaService := NewMFAService(repo, redis, &config.MFA, logger)
+	userRoleService := NewUserRoleService(repo, redis, auditService, &config.Role, logger)
+	userStatusService := NewUserStatusService(repo, jwt, userSessionService, userRoleService, auditService, logger)
+	userAccountService := NewUserAccountService(repo, redis, hasher, verifyCodeService, logger)
+	personalDataService := NewPersonalDataService(repo, jwt, redis, queue, userAccountService, auditService, &config.Privacy, logger)
 	return &ServiceImpl{
-		db:              db,
-		config:          config,
-		jwt:             jwt,
-		redis:           redis,
-		snowflake:       snowflake,
-		logger:          logger,
-		userService:     NewUserService(repo, redis, logger),
-		userRoleService: NewUserRoleService(repo, redis, logger),
-		authService:     NewAuthService(repo, jwt, logger),
+		db:                   db,
+		config:               config,
+		jwt:                  jwt,
+		redis:                redis,
+		snowflake:            snowflake,
+		logger:               logger,
+		userService:          NewUserService(repo, redis, auditService, logger),
+		userRoleService:      userRoleService,
+		authService:          NewAuthService(repo, jwt, redis, hasher, verifyCodeService, userSessionService, mfaService, userStatusService, loginEventService, oauth, &config.OAuth, &config.Login, logger),
+		verifyCodeService:    verifyCodeService,
+		userSessionService:   userSessionService,
+		mfaService:           mfaService,
+		apiKeyService:        NewAPIKeyService(repo, userStatusService, &config.APIKey, logger),
+		userAccountService:   userAccountService,
+		permissionService:    NewPermissionService(repo, redis, userRoleService, logger),
+		userStatusService:    userStatusService,
+		impersonationService: NewImpersonationService(repo, jwt, userRoleService, userStatusService, auditService, logger),
+		organizationService:  NewOrganizationService(repo, jwt, logger),
+		auditService:         auditService,
+		loginEventService:    loginEventService,
+		personalDataService:  personalDataService,
+		trashService:         NewTrashService(repo, jwt, userSessionService, userStatusService, personalDataService, auditService, &config.Trash, logger),
 	}
 }
 
@@ -54,3 +107,55 @@ func (s *ServiceImpl) UserRole() UserRoleService {
 func (s *ServiceImpl) Auth() AuthService {
 	return s.authService
 }
+
+func (s *ServiceImpl) VerifyCode() VerifyCodeService {
+	return s.verifyCodeService
+}
+
+func (s *ServiceImpl) UserSession() UserSessionService {
+	return s.userSessionService
+}
+
+func (s *ServiceImpl) MFA() MFAService {
+	return s.mfaService
+}
+
+func (s *ServiceImpl) APIKey() APIKeyService {
+	return s.apiKeyService
+}
+
+func (s *ServiceImpl) UserAccount() UserAccountService {
+	return s.userAccountService
+}
+
+func (s *ServiceImpl) Permission() PermissionService {
+	return s.permissionService
+}
+
+func (s *ServiceImpl) UserStatus() UserStatusService {
+	return s.userStatusService
+}
+
+func (s *ServiceImpl) Impersonation() ImpersonationService {
+	return s.impersonationService
+}
+
+func (s *ServiceImpl) Organization() OrganizationService {
+	return s.organizationService
+}
+
+func (s *ServiceImpl) Audit() AuditService {
+	return s.auditService
+}
+
+func (s *ServiceImpl) LoginEvent() LoginEventService {
+	return s.loginEventService
+}
+
+func (s *ServiceImpl) PersonalData() PersonalDataService {
+	return s.personalDataService
+}
+
+func (s *ServiceImpl) Trash() TrashService {
+	return s.trashService
+}
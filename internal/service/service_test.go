@@ -0,0 +1,32 @@
+package service
+
+import (
+	"go-server-starter/pkg/redis"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 每个测试独立的 SQLite 内存数据库
+func newTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// newTestRedis 每个测试独立的 miniredis
+func newTestRedis(t *testing.T) (*redis.Redis, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	return &redis.Redis{Client: goredis.NewClient(&goredis.Options{Addr: server.Addr()})}, server
+}
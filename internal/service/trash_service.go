@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TrashBin 一张表的回收站, Purge 彻底删除 before 之前软删除的行, 返回删除的行数
+type TrashBin struct {
+	Table string
+	Purge func(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TrashService 软删除数据的查询, 恢复和定时清理, 各表的保留时间见 TrashConfig
+type TrashService interface {
+	Register(bin TrashBin)
+	DeleteUser(ctx *ctx.Context, userID uint64) *exception.Exception
+	GetTrashedUsers(ctx *ctx.Context, params dto.UserTrashTableQueryReqDto) (*dto.PaginationResDto[[]*dto.UserTrashItemResDto], *exception.Exception)
+	RestoreUsers(ctx *ctx.Context, ids []uint64) *exception.Exception
+	Purge(ctx context.Context) error
+	HandlePurgeTask(ctx context.Context, task *asynq.Task) error
+}
+
+type TrashServiceImpl struct {
+	repo         repo.Repo
+	jwt          *jwt.JWT
+	session      UserSessionService
+	userStatus   UserStatusService
+	personalData PersonalDataService
+	audit        AuditService
+	config       *config.TrashConfig
+	logger       *zap.Logger
+	bins         []TrashBin
+}
+
+func NewTrashService(repo repo.Repo, jwt *jwt.JWT, session UserSessionService, userStatus UserStatusService, personalData PersonalDataService, audit AuditService, config *config.TrashConfig, logger *zap.Logger) TrashService {
+	s := &TrashServiceImpl{
+		repo:         repo,
+		jwt:          jwt,
+		session:      session,
+		userStatus:   userStatus,
+		personalData: personalData,
+		audit:        audit,
+		config:       config,
+		logger:       logger,
+	}
+	s.registerBuiltin()
+	return s
+}
+
+// Register 注册需要定时清理的表, 表名重复时覆盖
+func (s *TrashServiceImpl) Register(bin TrashBin) {
+	for i, b := range s.bins {
+		if b.Table == bin.Table {
+			s.bins[i] = bin
+			return
+		}
+	}
+	s.bins = append(s.bins, bin)
+}
+
+// DeleteUser 将用户移入回收站, 同时撤销令牌和会话, 数据保留到清理前都可以恢复
+func (s *TrashServiceImpl) DeleteUser(ctx *ctx.Context, userID uint64) *exception.Exception {
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return exception.UserNotFound
+	}
+	if exc := s.userStatus.CheckCanChange(ctx, user); exc != nil {
+		return exc
+	}
+	// 先撤销令牌, 撤销失败时不删除, 避免已删除的用户仍持有有效令牌
+	if err := s.jwt.RevokeUserTokens(ctx.Ctx, user.UniCode); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.repo.User().SoftDelete(ctx.Ctx, user.ID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	// 覆盖可能缓存的 active 状态, 撤销后删除前签发的令牌同样不能使用, 恢复时清除
+	if err := s.jwt.BlockUser(ctx.Ctx, user.UniCode, enum.UserStatusDeactivated, nil); err != nil {
+		s.logger.Warn("block trashed user failed", zap.String("uniCode", user.UniCode), zap.Error(err))
+	}
+	if exc := s.session.RemoveAllByUserID(ctx, user.ID); exc != nil {
+		return exc
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserTrash,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     map[string]any{"deleted": false},
+		After:      map[string]any{"deleted": true},
+	})
+	return nil
+}
+
+func (s *TrashServiceImpl) GetTrashedUsers(ctx *ctx.Context, params dto.UserTrashTableQueryReqDto) (*dto.PaginationResDto[[]*dto.UserTrashItemResDto], *exception.Exception) {
+	query, err := repo.ParseTableQuery(&params, params.Values)
+	if err != nil {
+		return nil, exception.InvalidParam.Append(err.Error())
+	}
+	sorts := query.Sorts
+	if len(sorts) == 0 {
+		sorts = []repo.CursorSort{{Column: "deleted_at", Desc: true}}
+	}
+	opts := append([]repo.QueryOption{repo.Preload("Roles")}, query.Filters...)
+	opts = append(opts, repo.OrderBy(sorts...))
+	users, total, err := s.repo.User().GetTrashed(ctx.Ctx, params.Page, params.PageSize, opts...)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	items := toUserListItemResDtos(users)
+	res := make([]*dto.UserTrashItemResDto, len(users))
+	for i, user := range users {
+		res[i] = &dto.UserTrashItemResDto{UserListItemResDto: *items[i]}
+		if user.DeletedAt.Valid {
+			res[i].DeletedAt = user.DeletedAt.Time.Format(time.RFC3339)
+		}
+	}
+	return utils.AssemblePaginationResDto(res, total, params.Page, params.PageSize), nil
+}
+
+// RestoreUsers 从回收站恢复用户, 用户名, 邮箱或手机号已被其他用户使用时不能恢复, 任一用户失败时全部回滚
+func (s *TrashServiceImpl) RestoreUsers(ctx *ctx.Context, ids []uint64) *exception.Exception {
+	var exc *exception.Exception
+	var uniCodes []string
+	err := s.repo.Tx().Do(ctx.Ctx, func(c context.Context) error {
+		uniCodes = uniCodes[:0]
+		for _, id := range ids {
+			user, err := s.repo.User().GetOne(c, repo.Unscoped(), repo.Where("id = ? AND deleted_at IS NOT NULL", id))
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if user == nil {
+				exc = exception.UserNotInTrash.Append(strconv.FormatUint(id, 10))
+				return errRestoreAborted
+			}
+			// 同一批中先恢复的用户也在事务中可见, 批内的重复同样会被发现, 并发的冲突由唯一索引兜底
+			conflict, err := s.repo.User().GetOne(c, repo.Where("id <> ?", id), restoreConflictCondition(user))
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if conflict != nil {
+				exc = exception.UserRestoreConflict.Append(user.UniCode)
+				return errRestoreAborted
+			}
+			if err := s.repo.User().Restore(c, id); err != nil {
+				if _, ok := repo.DuplicateKey(err); ok {
+					exc = exception.UserRestoreConflict.Append(user.UniCode)
+					return errRestoreAborted
+				}
+				return err
+			}
+			uniCodes = append(uniCodes, user.UniCode)
+		}
+		return nil
+	})
+	if exc != nil {
+		return exc
+	}
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	// 清除删除时写入的停用标记, 恢复后按数据库中的状态重新读取
+	for _, uniCode := range uniCodes {
+		if err := s.jwt.UnblockUser(ctx.Ctx, uniCode); err != nil {
+			s.logger.Warn("unblock restored user failed", zap.String("uniCode", uniCode), zap.Error(err))
+		}
+	}
+	for _, id := range ids {
+		s.audit.Record(ctx, AuditEntry{
+			Action:     AuditActionUserRestore,
+			TargetType: enum.AuditTargetTypeUser,
+			TargetID:   strconv.FormatUint(id, 10),
+			Before:     map[string]any{"deleted": true},
+			After:      map[string]any{"deleted": false},
+		})
+	}
+	return nil
+}
+
+// Purge 按保留时间清理各表的回收站, 单张表失败不影响其他表
+func (s *TrashServiceImpl) Purge(ctx context.Context) error {
+	ctx = repo.WithoutTenant(ctx)
+	var errs []error
+	for _, bin := range s.bins {
+		retention := s.config.RetentionOf(bin.Table)
+		if retention <= 0 {
+			continue
+		}
+		count, err := bin.Purge(ctx, time.Now().Add(-retention))
+		if err != nil {
+			s.logger.Error("purge trash failed", zap.String("table", bin.Table), zap.Int64("purged", count), zap.Error(err))
+			errs = append(errs, fmt.Errorf("purge %s: %w", bin.Table, err))
+			continue
+		}
+		if count > 0 {
+			s.logger.Info("trash purged", zap.String("table", bin.Table), zap.Int64("purged", count))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandlePurgeTask 定时任务中清理回收站
+func (s *TrashServiceImpl) HandlePurgeTask(ctx context.Context, task *asynq.Task) error {
+	return s.Purge(ctx)
+}
+
+// registerBuiltin 注册内置的回收站, 用户需要连同各模块的个人数据一起删除
+func (s *TrashServiceImpl) registerBuiltin() {
+	s.Register(TrashBin{
+		Table: model.User{}.TableName(),
+		Purge: func(ctx context.Context, before time.Time) (int64, error) {
+			users, err := s.repo.User().GetMany(ctx, repo.Unscoped(), repo.Where("deleted_at < ?", before))
+			if err != nil {
+				return 0, err
+			}
+			var count int64
+			for _, user := range users {
+				if err := s.personalData.Purge(ctx, user); err != nil {
+					return count, fmt.Errorf("user %d: %w", user.ID, err)
+				}
+				count++
+			}
+			return count, nil
+		},
+	})
+	s.Register(TrashBin{
+		Table: model.UserSession{}.TableName(),
+		Purge: s.repo.UserSession().PurgeOlderThan,
+	})
+}
+
+var errRestoreAborted = errors.New("restore aborted")
+
+// restoreConflictCondition 与其他用户的用户名, 邮箱或手机号相同
+func restoreConflictCondition(user *model.User) repo.QueryOption {
+	query, args := "1 = 0", []any{}
+	if user.Username != "" {
+		query, args = query+" OR username = ?", append(args, user.Username)
+	}
+	if user.Email != "" {
+		query, args = query+" OR email = ?", append(args, user.Email)
+	}
+	if user.Mobile != "" {
+		query, args = query+" OR (mobile = ? AND country_code = ?)", append(args, user.Mobile, user.CountryCode)
+	}
+	return repo.Where("("+query+")", args...)
+}
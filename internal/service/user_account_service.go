@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/password"
+	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// UserAccountService 管理账号的登录标识 (邮箱, 手机), 敏感操作需要先验证身份
+type UserAccountService interface {
+	SendReauthCode(ctx *ctx.Context, params dto.UserSendReauthCodeReqDto) *exception.Exception
+	Reauth(ctx *ctx.Context, params dto.UserReauthReqDto) (*dto.UserReauthResDto, *exception.Exception)
+	SendEmailBindCode(ctx *ctx.Context, params dto.UserSendEmailBindCodeReqDto) *exception.Exception
+	BindEmail(ctx *ctx.Context, params dto.UserBindEmailReqDto) *exception.Exception
+	UnbindEmail(ctx *ctx.Context, params dto.UserUnbindReqDto) *exception.Exception
+	SendMobileBindCode(ctx *ctx.Context, params dto.UserSendMobileBindCodeReqDto) *exception.Exception
+	BindMobile(ctx *ctx.Context, params dto.UserBindMobileReqDto) *exception.Exception
+	UnbindMobile(ctx *ctx.Context, params dto.UserUnbindReqDto) *exception.Exception
+	ConsumeReauth(ctx *ctx.Context, user *model.User, token string) *exception.Exception
+}
+
+type UserAccountServiceImpl struct {
+	repo       repo.Repo
+	redis      *redis.Redis
+	hasher     *password.Hasher
+	verifyCode VerifyCodeService
+	logger     *zap.Logger
+}
+
+func NewUserAccountService(repo repo.Repo, redis *redis.Redis, hasher *password.Hasher, verifyCode VerifyCodeService, logger *zap.Logger) UserAccountService {
+	return &UserAccountServiceImpl{
+		repo:       repo,
+		redis:      redis,
+		hasher:     hasher,
+		verifyCode: verifyCode,
+		logger:     logger,
+	}
+}
+
+// SendReauthCode 向当前绑定的邮箱或手机发送身份验证码
+func (s *UserAccountServiceImpl) SendReauthCode(ctx *ctx.Context, params dto.UserSendReauthCodeReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	target, exc := reauthTarget(user, params.Channel)
+	if exc != nil {
+		return exc
+	}
+	return s.verifyCode.Send(ctx, enum.VerifyCodeSceneReauth, params.Channel, target)
+}
+
+// Reauth 验证身份后签发一次性的 reauthToken, 用于更换或解绑登录标识
+func (s *UserAccountServiceImpl) Reauth(ctx *ctx.Context, params dto.UserReauthReqDto) (*dto.UserReauthResDto, *exception.Exception) {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	if params.Password != "" {
+		if user.Password == "" {
+			return nil, exception.UserPasswordNotSet
+		}
+		if !s.hasher.Compare(params.Password, user.Salt, user.Password) {
+			return nil, exception.UserPasswordIsIncorrect
+		}
+	} else {
+		target, exc := reauthTarget(user, params.Channel)
+		if exc != nil {
+			return nil, exc
+		}
+		if exc := s.verifyCode.Verify(ctx, enum.VerifyCodeSceneReauth, params.Channel, target, params.Code); exc != nil {
+			return nil, exc
+		}
+	}
+	token, err := utils.SecureRandomCode(32, utils.AlphaAll, utils.Digits)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.redis.Set(ctx.Ctx, constant.RedisKeyOfReauth(token), user.ID, constant.REDIS_EXPIRE_OF_REAUTH).Err(); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return &dto.UserReauthResDto{
+		ReauthToken: token,
+		ExpiresAt:   time.Now().Add(constant.REDIS_EXPIRE_OF_REAUTH).Format(time.RFC3339),
+	}, nil
+}
+
+func (s *UserAccountServiceImpl) SendEmailBindCode(ctx *ctx.Context, params dto.UserSendEmailBindCodeReqDto) *exception.Exception {
+	email := strings.ToLower(strings.TrimSpace(params.Email))
+	if exc := s.checkEmailAvailable(ctx, email); exc != nil {
+		return exc
+	}
+	return s.verifyCode.Send(ctx, enum.VerifyCodeSceneBind, enum.VerifyCodeChannelEmail, email)
+}
+
+// BindEmail 绑定邮箱, 已绑定时为更换, 需要先验证身份
+func (s *UserAccountServiceImpl) BindEmail(ctx *ctx.Context, params dto.UserBindEmailReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	// 更换时需要先验证身份, 令牌在验证码通过后再作废, 输错验证码不用重新验证身份
+	replace := user.Email != ""
+	if replace && params.ReauthToken == "" {
+		return exception.UserReauthRequired
+	}
+	email := strings.ToLower(strings.TrimSpace(params.Email))
+	if exc := s.checkEmailAvailable(ctx, email); exc != nil {
+		return exc
+	}
+	if exc := s.verifyCode.Verify(ctx, enum.VerifyCodeSceneBind, enum.VerifyCodeChannelEmail, email, params.Code); exc != nil {
+		return exc
+	}
+	if replace {
+		if exc := s.ConsumeReauth(ctx, user, params.ReauthToken); exc != nil {
+			return exc
+		}
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, map[string]any{"email": email}); err != nil {
+		if exc := userDuplicateException(err); exc != nil {
+			return exc
+		}
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserAccountServiceImpl) UnbindEmail(ctx *ctx.Context, params dto.UserUnbindReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	if user.Email == "" {
+		return exception.UserEmailHasNotBeenRegistered
+	}
+	remaining := *user
+	remaining.Email = ""
+	if exc := s.checkHasLoginIdentifier(ctx, &remaining); exc != nil {
+		return exc
+	}
+	if exc := s.ConsumeReauth(ctx, user, params.ReauthToken); exc != nil {
+		return exc
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, map[string]any{"email": ""}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserAccountServiceImpl) SendMobileBindCode(ctx *ctx.Context, params dto.UserSendMobileBindCodeReqDto) *exception.Exception {
+	mobile := strings.ReplaceAll(params.Mobile, " ", "")
+	if exc := s.checkMobileAvailable(ctx, params.CountryCode, mobile); exc != nil {
+		return exc
+	}
+	return s.verifyCode.Send(ctx, enum.VerifyCodeSceneBind, enum.VerifyCodeChannelMobile, MobileTarget(params.CountryCode, mobile))
+}
+
+// BindMobile 绑定手机, 已绑定时为更换, 需要先验证身份
+func (s *UserAccountServiceImpl) BindMobile(ctx *ctx.Context, params dto.UserBindMobileReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	replace := user.Mobile != ""
+	if replace && params.ReauthToken == "" {
+		return exception.UserReauthRequired
+	}
+	mobile := strings.ReplaceAll(params.Mobile, " ", "")
+	if exc := s.checkMobileAvailable(ctx, params.CountryCode, mobile); exc != nil {
+		return exc
+	}
+	if exc := s.verifyCode.Verify(ctx, enum.VerifyCodeSceneBind, enum.VerifyCodeChannelMobile, MobileTarget(params.CountryCode, mobile), params.Code); exc != nil {
+		return exc
+	}
+	if replace {
+		if exc := s.ConsumeReauth(ctx, user, params.ReauthToken); exc != nil {
+			return exc
+		}
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, map[string]any{"mobile": mobile, "country_code": params.CountryCode}); err != nil {
+		if exc := userDuplicateException(err); exc != nil {
+			return exc
+		}
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserAccountServiceImpl) UnbindMobile(ctx *ctx.Context, params dto.UserUnbindReqDto) *exception.Exception {
+	user, exc := currentUser(ctx, s.repo)
+	if exc != nil {
+		return exc
+	}
+	if user.Mobile == "" {
+		return exception.UserMobileHasNotBeenRegistered
+	}
+	remaining := *user
+	remaining.Mobile = ""
+	if exc := s.checkHasLoginIdentifier(ctx, &remaining); exc != nil {
+		return exc
+	}
+	if exc := s.ConsumeReauth(ctx, user, params.ReauthToken); exc != nil {
+		return exc
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, map[string]any{"mobile": "", "country_code": ""}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+// ConsumeReauth 校验并作废 reauthToken, 令牌只能使用一次且必须属于当前用户
+func (s *UserAccountServiceImpl) ConsumeReauth(ctx *ctx.Context, user *model.User, token string) *exception.Exception {
+	if token == "" {
+		return exception.UserReauthRequired
+	}
+	value, err := s.redis.GetDel(ctx.Ctx, constant.RedisKeyOfReauth(token)).Result()
+	if err != nil && err != goredis.Nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if value != strconv.FormatUint(user.ID, 10) {
+		return exception.UserReauthTokenInvalid
+	}
+	return nil
+}
+
+// checkHasLoginIdentifier 解绑后至少还要保留一种登录方式
+func (s *UserAccountServiceImpl) checkHasLoginIdentifier(ctx *ctx.Context, user *model.User) *exception.Exception {
+	if user.Email != "" || user.Mobile != "" || (user.Username != "" && user.Password != "") {
+		return nil
+	}
+	identities, err := s.repo.UserIdentity().GetMany(ctx.Ctx, repo.Where("user_id = ?", user.ID))
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if len(identities) == 0 {
+		return exception.UserLastLoginIdentifier
+	}
+	return nil
+}
+
+func (s *UserAccountServiceImpl) checkEmailAvailable(ctx *ctx.Context, email string) *exception.Exception {
+	user, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("email = ?", email))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if user != nil {
+		return exception.UserEmailHasBeenRegistered
+	}
+	return nil
+}
+
+func (s *UserAccountServiceImpl) checkMobileAvailable(ctx *ctx.Context, countryCode string, mobile string) *exception.Exception {
+	user, err := s.repo.User().GetOne(ctx.Ctx, repo.Where("mobile = ? AND country_code = ?", mobile, countryCode))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if user != nil {
+		return exception.UserMobileHasBeenRegistered
+	}
+	return nil
+}
+
+// reauthTarget 身份验证码只能发送到当前已绑定的邮箱或手机
+func reauthTarget(user *model.User, channel enum.VerifyCodeChannel) (string, *exception.Exception) {
+	switch channel {
+	case enum.VerifyCodeChannelEmail:
+		if user.Email == "" {
+			return "", exception.UserEmailHasNotBeenRegistered
+		}
+		return user.Email, nil
+	case enum.VerifyCodeChannelMobile:
+		if user.Mobile == "" {
+			return "", exception.UserMobileHasNotBeenRegistered
+		}
+		return MobileTarget(user.CountryCode, user.Mobile), nil
+	default:
+		return "", exception.InvalidParam
+	}
+}
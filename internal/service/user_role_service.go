@@ -1,39 +1,65 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"go-server-starter/internal/config"
 	"go-server-starter/internal/constant"
 	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
 	"go-server-starter/internal/enum"
 	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
 	"go-server-starter/internal/repo"
 	"go-server-starter/pkg/redis"
+	"slices"
+	"strconv"
 
 	goredis "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type UserRoleService interface {
 	GetRolesCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.RoleCode, *exception.Exception)
 	GetCachedRolesCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.RoleCode, *exception.Exception)
+	GetEffectiveRolesCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.RoleCode, *exception.Exception)
+	GetUserEffectiveRoles(ctx *ctx.Context, userID uint64) ([]string, *exception.Exception)
+	ExpandRoles(roles []enum.RoleCode) []enum.RoleCode
+	GetRoles(ctx *ctx.Context) ([]*dto.UserRoleResDto, *exception.Exception)
+	SetRoleEnabled(ctx *ctx.Context, roleID uint64, params dto.UserRoleSetEnabledReqDto) *exception.Exception
+	GetUserRoles(ctx *ctx.Context, userID uint64) ([]*dto.UserRoleResDto, *exception.Exception)
+	AssignUserRole(ctx *ctx.Context, userID uint64, params dto.UserRoleAssignReqDto) *exception.Exception
+	RevokeUserRole(ctx *ctx.Context, userID uint64, roleID uint64) *exception.Exception
+	ClearCache(ctx *ctx.Context, uniCodes ...string) *exception.Exception
 }
 
 type UserRoleServiceImpl struct {
 	repo   repo.Repo
 	redis  *redis.Redis
+	audit  AuditService
+	config *config.RoleConfig
 	logger *zap.Logger
 }
 
-func NewUserRoleService(repo repo.Repo, redis *redis.Redis, logger *zap.Logger) UserRoleService {
+func NewUserRoleService(repo repo.Repo, redis *redis.Redis, audit AuditService, config *config.RoleConfig, logger *zap.Logger) UserRoleService {
 	return &UserRoleServiceImpl{
 		repo:   repo,
 		redis:  redis,
+		audit:  audit,
+		config: config,
 		logger: logger,
 	}
 }
 
 func (s *UserRoleServiceImpl) GetRolesCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.RoleCode, *exception.Exception) {
-	roles, err := s.repo.User().GetRolesByUniCode(ctx.Ctx, uniCode)
+	return s.getRolesCode(ctx.Ctx, uniCode)
+}
+
+// getRolesCode 写入缓存前读主库, 避免把副本上的旧数据缓存下来
+func (s *UserRoleServiceImpl) getRolesCode(ctx context.Context, uniCode string) ([]enum.RoleCode, *exception.Exception) {
+	roles, err := s.repo.User().GetRolesByUniCode(ctx, uniCode)
 	if err != nil {
 		s.logger.Error("get roles code by uni code failed", zap.String("uniCode", uniCode), zap.Error(err))
 		return nil, exception.InternalServerError.Append(err.Error())
@@ -54,7 +80,7 @@ func (s *UserRoleServiceImpl) GetCachedRolesCodeByUniCode(ctx *ctx.Context, uniC
 			return nil, exception.InternalServerError.Append(err.Error())
 		} else {
 			// 如果redis 正常报错（goredis.Nil），则获取数据库中的角色
-			roles, exc := s.GetRolesCodeByUniCode(ctx, uniCode)
+			roles, exc := s.getRolesCode(repo.WithPrimary(ctx.Ctx), uniCode)
 			if exc != nil {
 				return nil, exc
 			}
@@ -81,3 +107,311 @@ func (s *UserRoleServiceImpl) GetCachedRolesCodeByUniCode(ctx *ctx.Context, uniC
 		return roles, nil
 	}
 }
+
+// GetEffectiveRolesCodeByUniCode 用户直接拥有的角色按继承关系展开, 去掉已禁用的角色后缓存
+func (s *UserRoleServiceImpl) GetEffectiveRolesCodeByUniCode(ctx *ctx.Context, uniCode string) ([]enum.RoleCode, *exception.Exception) {
+	key := constant.RedisKeyOfAuthEffectiveRoles(uniCode)
+	dataStr, err := s.redis.Get(ctx.Ctx, key).Result()
+	if err != nil && err != goredis.Nil {
+		s.logger.Error("get cached effective roles failed", zap.String("uniCode", uniCode), zap.Error(err))
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if err == nil {
+		var roles []enum.RoleCode
+		if err := json.Unmarshal([]byte(dataStr), &roles); err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		return roles, nil
+	}
+	// 结果会缓存, 从主库读取
+	primary := repo.WithPrimary(ctx.Ctx)
+	direct, exc := s.getRolesCode(primary, uniCode)
+	if exc != nil {
+		return nil, exc
+	}
+	// 继承来的角色被禁用时同样不生效
+	disabled, err := s.repo.UserRole().GetMany(primary, repo.Where("enabled = ?", false))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	roles := slices.DeleteFunc(s.ExpandRoles(direct), func(code enum.RoleCode) bool {
+		return slices.ContainsFunc(disabled, func(role *model.UserRole) bool { return role.Code == code })
+	})
+	rolesJSON, err := json.Marshal(roles)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.redis.Set(ctx.Ctx, key, rolesJSON, constant.REDIS_EXPIRE_OF_AUTH_ROLES).Err(); err != nil {
+		s.logger.Error("set cached effective roles failed", zap.String("uniCode", uniCode), zap.Error(err))
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	return roles, nil
+}
+
+func (s *UserRoleServiceImpl) GetUserEffectiveRoles(ctx *ctx.Context, userID uint64) ([]string, *exception.Exception) {
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	roles, exc := s.GetEffectiveRolesCodeByUniCode(ctx, user.UniCode)
+	if exc != nil {
+		return nil, exc
+	}
+	res := make([]string, len(roles))
+	for i, role := range roles {
+		res[i] = role.String()
+	}
+	return res, nil
+}
+
+// ExpandRoles 按继承关系展开角色, 允许配置中出现环
+func (s *UserRoleServiceImpl) ExpandRoles(roles []enum.RoleCode) []enum.RoleCode {
+	res := make([]enum.RoleCode, 0, len(roles))
+	seen := make(map[enum.RoleCode]struct{}, len(roles))
+	queue := slices.Clone(roles)
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[role]; ok {
+			continue
+		}
+		seen[role] = struct{}{}
+		res = append(res, role)
+		queue = append(queue, s.config.Inherits[role]...)
+	}
+	return res
+}
+
+// inheritors 直接或间接继承该角色的所有角色, 包括它本身
+func (s *UserRoleServiceImpl) inheritors(role enum.RoleCode) []enum.RoleCode {
+	res := []enum.RoleCode{role}
+	for changed := true; changed; {
+		changed = false
+		for parent, children := range s.config.Inherits {
+			if slices.Contains(res, parent) {
+				continue
+			}
+			if slices.ContainsFunc(children, func(child enum.RoleCode) bool { return slices.Contains(res, child) }) {
+				res = append(res, parent)
+				changed = true
+			}
+		}
+	}
+	return res
+}
+
+func (s *UserRoleServiceImpl) GetRoles(ctx *ctx.Context) ([]*dto.UserRoleResDto, *exception.Exception) {
+	roles, err := s.repo.UserRole().GetMany(ctx.Ctx, repo.Order("id ASC"), repo.Preload("Permissions"))
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	res := make([]*dto.UserRoleResDto, len(roles))
+	for i, role := range roles {
+		res[i] = toUserRoleResDto(role)
+	}
+	return res, nil
+}
+
+// SetRoleEnabled 启用或禁用角色, 立即清除所有持有该角色用户的缓存
+func (s *UserRoleServiceImpl) SetRoleEnabled(ctx *ctx.Context, roleID uint64, params dto.UserRoleSetEnabledReqDto) *exception.Exception {
+	role, exc := s.getRole(ctx, roleID)
+	if exc != nil {
+		return exc
+	}
+	if role.Code == enum.RoleCodeSuperAdmin && !*params.Enabled {
+		return exception.UserRoleCannotDisable
+	}
+	if exc := s.checkCanManage(ctx, role); exc != nil {
+		return exc
+	}
+	if role.Enabled == *params.Enabled {
+		return nil
+	}
+	if err := s.repo.UserRole().UpdateByMap(ctx.Ctx, role.ID, map[string]any{"enabled": *params.Enabled}); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionRoleSetEnabled,
+		TargetType: enum.AuditTargetTypeRole,
+		TargetID:   role.Code.String(),
+		Before:     map[string]any{"enabled": role.Enabled},
+		After:      map[string]any{"enabled": *params.Enabled},
+	})
+	// 继承该角色的用户的有效角色也会变化
+	roles, err := s.repo.UserRole().GetMany(ctx.Ctx, repo.Where("code IN ?", s.inheritors(role.Code)))
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	var uniCodes []string
+	for _, r := range roles {
+		holders, err := s.repo.UserRole().GetUserUniCodesByRoleID(ctx.Ctx, r.ID)
+		if err != nil {
+			return exception.InternalServerError.Append(err.Error())
+		}
+		uniCodes = append(uniCodes, holders...)
+	}
+	return s.ClearCache(ctx, uniCodes...)
+}
+
+func (s *UserRoleServiceImpl) GetUserRoles(ctx *ctx.Context, userID uint64) ([]*dto.UserRoleResDto, *exception.Exception) {
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID, repo.Preload("Roles.Permissions"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	res := make([]*dto.UserRoleResDto, len(user.Roles))
+	for i := range user.Roles {
+		res[i] = toUserRoleResDto(&user.Roles[i])
+	}
+	return res, nil
+}
+
+func (s *UserRoleServiceImpl) AssignUserRole(ctx *ctx.Context, userID uint64, params dto.UserRoleAssignReqDto) *exception.Exception {
+	role, exc := s.getRole(ctx, params.RoleID)
+	if exc != nil {
+		return exc
+	}
+	if exc := s.checkCanManage(ctx, role); exc != nil {
+		return exc
+	}
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID, repo.Preload("Roles"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return exception.UserNotFound
+	}
+	if slices.ContainsFunc(user.Roles, func(r model.UserRole) bool { return r.ID == role.ID }) {
+		return nil
+	}
+	if err := s.repo.UserRole().AssignToUser(ctx.Ctx, user.ID, role); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	before := roleCodes(user.Roles)
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserRoleAssign,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     map[string]any{"roles": before},
+		After:      map[string]any{"roles": append(before, role.Code.String())},
+	})
+	return s.ClearCache(ctx, user.UniCode)
+}
+
+func (s *UserRoleServiceImpl) RevokeUserRole(ctx *ctx.Context, userID uint64, roleID uint64) *exception.Exception {
+	role, exc := s.getRole(ctx, roleID)
+	if exc != nil {
+		return exc
+	}
+	if exc := s.checkCanManage(ctx, role); exc != nil {
+		return exc
+	}
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID, repo.Preload("Roles"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return exception.UserNotFound
+	}
+	if !slices.ContainsFunc(user.Roles, func(r model.UserRole) bool { return r.ID == role.ID }) {
+		return nil
+	}
+	if role.Code == enum.RoleCodeSuperAdmin {
+		holders, err := s.repo.UserRole().GetUserUniCodesByRoleID(ctx.Ctx, role.ID)
+		if err != nil {
+			return exception.InternalServerError.Append(err.Error())
+		}
+		if len(holders) <= 1 {
+			return exception.UserRoleLastSuperAdmin
+		}
+	}
+	if err := s.repo.UserRole().RevokeFromUser(ctx.Ctx, user.ID, role); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	before := roleCodes(user.Roles)
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserRoleRevoke,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     map[string]any{"roles": before},
+		After:      map[string]any{"roles": slices.DeleteFunc(slices.Clone(before), func(code string) bool { return code == role.Code.String() })},
+	})
+	return s.ClearCache(ctx, user.UniCode)
+}
+
+// ClearCache 清除用户的角色和权限缓存, 角色变更后立即生效
+func (s *UserRoleServiceImpl) ClearCache(ctx *ctx.Context, uniCodes ...string) *exception.Exception {
+	if len(uniCodes) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(uniCodes)*3)
+	for _, uniCode := range uniCodes {
+		keys = append(keys,
+			constant.RedisKeyOfAuthRoles(uniCode),
+			constant.RedisKeyOfAuthEffectiveRoles(uniCode),
+			constant.RedisKeyOfAuthPermissions(uniCode),
+		)
+	}
+	if err := s.redis.Del(ctx.Ctx, keys...).Err(); err != nil {
+		s.logger.Error("clear role cache failed", zap.Int("users", len(uniCodes)), zap.Error(err))
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserRoleServiceImpl) getRole(ctx *ctx.Context, roleID uint64) (*model.UserRole, *exception.Exception) {
+	role, err := s.repo.UserRole().GetByID(ctx.Ctx, roleID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if role == nil {
+		return nil, exception.UserRoleNotFound
+	}
+	return role, nil
+}
+
+// checkCanManage 管理员角色及继承了管理员角色的角色只有超级管理员可以授予, 撤销或禁用
+func (s *UserRoleServiceImpl) checkCanManage(ctx *ctx.Context, role *model.UserRole) *exception.Exception {
+	expanded := s.ExpandRoles([]enum.RoleCode{role.Code})
+	if !slices.Contains(expanded, enum.RoleCodeAdmin) && !slices.Contains(expanded, enum.RoleCodeSuperAdmin) {
+		return nil
+	}
+	uniCode, exc := ctx.GetUserUniCode()
+	if exc != nil {
+		return exc
+	}
+	roles, exc := s.GetEffectiveRolesCodeByUniCode(ctx, uniCode)
+	if exc != nil {
+		return exc
+	}
+	if !slices.Contains(roles, enum.RoleCodeSuperAdmin) {
+		return exception.UserRoleGrantForbidden
+	}
+	return nil
+}
+
+func roleCodes(roles []model.UserRole) []string {
+	res := make([]string, len(roles))
+	for i, role := range roles {
+		res[i] = role.Code.String()
+	}
+	return res
+}
+
+func toUserRoleResDto(role *model.UserRole) *dto.UserRoleResDto {
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Code.String()
+	}
+	return &dto.UserRoleResDto{
+		ID:          role.ID,
+		Code:        role.Code.String(),
+		Enabled:     role.Enabled,
+		Permissions: permissions,
+	}
+}
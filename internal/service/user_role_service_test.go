@@ -0,0 +1,46 @@
+package service
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestAssignRoleInheritingAdminRequiresSuperAdmin(t *testing.T) {
+	db := newTestDB(t, &model.Permission{}, &model.UserRole{}, &model.User{})
+	rds, _ := newTestRedis(t)
+	r := repo.NewRepo(db, zap.NewNop())
+	// user_svip 继承了 admin, 授予它等同于授予管理员
+	roleConfig := &config.RoleConfig{Inherits: map[enum.RoleCode][]enum.RoleCode{
+		enum.RoleCodeUserSvip: {enum.RoleCodeAdmin},
+	}}
+	s := NewUserRoleService(r, rds, nil, roleConfig, zap.NewNop())
+
+	admin := &model.UserRole{Code: enum.RoleCodeAdmin, Enabled: true}
+	svip := &model.UserRole{Code: enum.RoleCodeUserSvip, Enabled: true}
+	for _, role := range []*model.UserRole{admin, svip} {
+		if err := db.Create(role).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	actor := &model.User{UniCode: "admin", Roles: []model.UserRole{*admin}}
+	target := &model.User{UniCode: "target"}
+	for _, user := range []*model.User{actor, target} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newTestCtx()
+	c.SetUserUniCode(actor.UniCode)
+	exc := s.AssignUserRole(c, target.ID, dto.UserRoleAssignReqDto{RoleID: svip.ID})
+	if exc == nil || exc.Code != exception.UserRoleGrantForbidden.Code {
+		t.Fatalf("AssignUserRole = %v, want UserRoleGrantForbidden", exc)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"errors"
 	"go-server-starter/internal/ctx"
 	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
 	"go-server-starter/internal/exception"
 	"go-server-starter/internal/model"
 	"go-server-starter/internal/repo"
 	"go-server-starter/pkg/redis"
 	"go-server-starter/pkg/utils"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,18 +21,20 @@ type UserService interface {
 	GetByID(ctx *ctx.Context, id uint64) (*model.User, *exception.Exception)
 	GetByUniCode(ctx *ctx.Context, uniCode string) (*model.User, *exception.Exception)
 	GetInfoByUniCode(ctx *ctx.Context, uniCode string) (*dto.UserInfoResDto, *exception.Exception)
-	GetTable(ctx *ctx.Context, params dto.UserTableQueryReqDto) (*dto.PaginationResDto[[]*dto.UserListItemResDto], *exception.Exception)
+	GetTable(ctx *ctx.Context, params dto.UserTableQueryReqDto) (any, *exception.Exception)
 	UpdateInfo(ctx *ctx.Context, params dto.UserUpdateInfoReqDto) (*dto.UserInfoResDto, *exception.Exception)
 }
 
 type UserServiceImpl struct {
 	repo   repo.Repo
+	audit  AuditService
 	logger *zap.Logger
 }
 
-func NewUserService(repo repo.Repo, redis *redis.Redis, logger *zap.Logger) UserService {
+func NewUserService(repo repo.Repo, redis *redis.Redis, audit AuditService, logger *zap.Logger) UserService {
 	return &UserServiceImpl{
 		repo:   repo,
+		audit:  audit,
 		logger: logger,
 	}
 }
@@ -71,52 +75,53 @@ func (s *UserServiceImpl) GetInfoByUniCode(ctx *ctx.Context, uniCode string) (*d
 
 	return &dto.UserInfoResDto{
 		UniCode:     user.UniCode,
+		Username:    user.Username,
 		Email:       user.Email,
 		Mobile:      user.Mobile,
 		CountryCode: user.CountryCode,
 		Desc:        user.Desc,
 		Nickname:    user.Nickname,
 		AvatarURL:   user.AvatarURL,
+		HasPassword: user.Password != "",
 		Roles:       roles,
+		Version:     user.Version,
 	}, nil
 }
 
-func (s *UserServiceImpl) GetTable(ctx *ctx.Context, params dto.UserTableQueryReqDto) (*dto.PaginationResDto[[]*dto.UserListItemResDto], *exception.Exception) {
-	opts := []repo.QueryOption{
-		repo.Order("created_at DESC"),
-		repo.Preload("Roles"),
-		repo.WhereAutoLike("nickname", params.Nickname),
-		repo.WhereAutoLikePrefix("email", params.Email),
-		repo.WhereAutoLikePrefix("mobile", params.Mobile),
-		repo.WhereAutoLikePrefix("country_code", params.CountryCode),
-	}
-	users, total, err := s.repo.User().GetTable(ctx.Ctx, params.Page, params.PageSize, opts...)
+// GetTable 带 cursor 参数时返回 CursorPaginationResDto, 否则返回 PaginationResDto
+func (s *UserServiceImpl) GetTable(ctx *ctx.Context, params dto.UserTableQueryReqDto) (any, *exception.Exception) {
+	query, err := repo.ParseTableQuery(&params, params.Values)
 	if err != nil {
-		return nil, exception.InternalServerError.Append(err.Error())
+		return nil, exception.InvalidParam.Append(err.Error())
 	}
-	res := make([]*dto.UserListItemResDto, len(users))
-	for i, user := range users {
-		res[i] = &dto.UserListItemResDto{
-			ID:          user.ID,
-			CreatedAt:   user.CreatedAt.Format(time.RFC3339),
-			UniCode:     user.UniCode,
-			Email:       user.Email,
-			Mobile:      user.Mobile,
-			CountryCode: user.CountryCode,
-			Desc:        user.Desc,
-			Nickname:    user.Nickname,
-			AvatarURL:   user.AvatarURL,
+	sorts := query.Sorts
+	if len(sorts) == 0 {
+		sorts = []repo.CursorSort{{Column: "created_at", Desc: true}}
+	}
+	opts := append([]repo.QueryOption{repo.Preload("Roles")}, query.Filters...)
+	if params.Cursor != nil {
+		page, err := s.repo.User().GetPage(ctx.Ctx, repo.CursorPageParams{Cursor: *params.Cursor, PageSize: params.PageSize, Sorts: sorts}, opts...)
+		if errors.Is(err, repo.ErrInvalidCursor) {
+			return nil, exception.InvalidParam.Append(err.Error())
 		}
-		roles := make([]string, len(user.Roles))
-		for i, role := range user.Roles {
-			roles[i] = role.Code.String()
+		if err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
 		}
-		res[i].Roles = roles
+		return assembleCursorPaginationResDto(page, toUserListItemResDtos(page.Items), params.PageSize), nil
+	}
+	opts = append(opts, repo.OrderBy(sorts...))
+	users, total, err := s.repo.User().GetTable(ctx.Ctx, params.Page, params.PageSize, opts...)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
 	}
-	return utils.AssemblePaginationResDto(res, total, params.Page, params.PageSize), nil
+	return utils.AssemblePaginationResDto(toUserListItemResDtos(users), total, params.Page, params.PageSize), nil
 }
 
 func (s *UserServiceImpl) UpdateInfo(ctx *ctx.Context, params dto.UserUpdateInfoReqDto) (*dto.UserInfoResDto, *exception.Exception) {
+	// 必须带上读取到的版本号, 避免覆盖并发的修改
+	if params.Version == nil {
+		return nil, exception.PreconditionRequired
+	}
 	userID, exc := ctx.GetUserID(s.repo)
 	if exc != nil {
 		return nil, exc
@@ -128,6 +133,7 @@ func (s *UserServiceImpl) UpdateInfo(ctx *ctx.Context, params dto.UserUpdateInfo
 	if user == nil {
 		return nil, exception.UserNotFound
 	}
+	before := map[string]any{"nickname": user.Nickname, "avatarURL": user.AvatarURL, "desc": user.Desc}
 	if params.Nickname != nil {
 		user.Nickname = *params.Nickname
 	}
@@ -137,21 +143,106 @@ func (s *UserServiceImpl) UpdateInfo(ctx *ctx.Context, params dto.UserUpdateInfo
 	if params.Desc != nil {
 		user.Desc = *params.Desc
 	}
-	if err := s.repo.User().UpdateByZeroFields(ctx.Ctx, userID, user); err != nil {
+	version := *params.Version
+	updates := map[string]any{"nickname": user.Nickname, "avatar_url": user.AvatarURL, "desc": user.Desc}
+	if err := s.repo.User().UpdateByMapWithVersion(ctx.Ctx, userID, version, updates); err != nil {
+		if errors.Is(err, repo.ErrVersionConflict) {
+			return nil, exception.VersionConflict
+		}
 		return nil, exception.UserUpdateInfoFailed.Append(err.Error())
 	}
+	user.Version = version + 1
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserUpdateInfo,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     before,
+		After:      map[string]any{"nickname": user.Nickname, "avatarURL": user.AvatarURL, "desc": user.Desc},
+	})
 	roles := make([]string, len(user.Roles))
 	for i, role := range user.Roles {
 		roles[i] = role.Code.String()
 	}
 	return &dto.UserInfoResDto{
 		UniCode:     user.UniCode,
+		Username:    user.Username,
 		Email:       user.Email,
 		Mobile:      user.Mobile,
 		CountryCode: user.CountryCode,
 		Desc:        user.Desc,
 		Nickname:    user.Nickname,
 		AvatarURL:   user.AvatarURL,
+		HasPassword: user.Password != "",
 		Roles:       roles,
+		Version:     user.Version,
 	}, nil
 }
+
+// currentUser 获取当前登录的用户
+func currentUser(ctx *ctx.Context, r repo.Repo) (*model.User, *exception.Exception) {
+	uniCode, exc := ctx.GetUserUniCode()
+	if exc != nil {
+		return nil, exc
+	}
+	user, err := r.User().GetByUniCode(ctx.Ctx, uniCode)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	return user, nil
+}
+
+// userDuplicateException 用户名, 邮箱或手机号的唯一索引冲突对应的异常, 不是这些冲突时返回 nil
+func userDuplicateException(err error) *exception.Exception {
+	key, ok := repo.DuplicateKey(err)
+	if !ok {
+		return nil
+	}
+	switch key {
+	case model.UserUsernameUniqueIndex:
+		return exception.UserUsernameHasBeenRegistered
+	case model.UserEmailUniqueIndex:
+		return exception.UserEmailHasBeenRegistered
+	case model.UserMobileUniqueIndex:
+		return exception.UserMobileHasBeenRegistered
+	default:
+		return nil
+	}
+}
+
+func toUserListItemResDtos(users []*model.User) []*dto.UserListItemResDto {
+	res := make([]*dto.UserListItemResDto, len(users))
+	for i, user := range users {
+		res[i] = &dto.UserListItemResDto{
+			ID:          user.ID,
+			CreatedAt:   user.CreatedAt.Format(time.RFC3339),
+			UniCode:     user.UniCode,
+			Email:       user.Email,
+			Mobile:      user.Mobile,
+			CountryCode: user.CountryCode,
+			Desc:        user.Desc,
+			Nickname:    user.Nickname,
+			AvatarURL:   user.AvatarURL,
+		}
+		roles := make([]string, len(user.Roles))
+		for i, role := range user.Roles {
+			roles[i] = role.Code.String()
+		}
+		res[i].Roles = roles
+	}
+	return res
+}
+
+func assembleCursorPaginationResDto[T any, R any](page *repo.CursorPage[T], data []R, pageSize int) *dto.CursorPaginationResDto[[]R] {
+	_, pageSize = utils.NormalizePageAndPageSize(1, pageSize)
+	return &dto.CursorPaginationResDto[[]R]{
+		Data:       data,
+		PageSize:   pageSize,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		HasNext:    page.HasNext,
+		HasPrev:    page.HasPrev,
+	}
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/exception"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestUpdateInfoRequiresVersion(t *testing.T) {
+	s := NewUserService(nil, nil, nil, zap.NewNop())
+	nickname := "nick"
+	_, exc := s.UpdateInfo(newTestCtx(), dto.UserUpdateInfoReqDto{Nickname: &nickname})
+	if exc == nil || exc.Code != exception.PreconditionRequired.Code || exc.StatusCode != http.StatusPreconditionRequired {
+		t.Fatalf("UpdateInfo without version = %v, want PreconditionRequired", exc)
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"errors"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserSessionService interface {
+	Create(ctx *ctx.Context, userID uint64, pair *jwt.TokenPair) *exception.Exception
+	Touch(ctx *ctx.Context, pair *jwt.TokenPair) *exception.Exception
+	RemoveByTokenID(ctx *ctx.Context, tokenID string) *exception.Exception
+	RemoveAllByUserID(ctx *ctx.Context, userID uint64) *exception.Exception
+	GetMySessions(ctx *ctx.Context) ([]*dto.UserSessionResDto, *exception.Exception)
+	RevokeMySession(ctx *ctx.Context, sessionID uint64) *exception.Exception
+	GetUserSessions(ctx *ctx.Context, userID uint64) ([]*dto.UserSessionResDto, *exception.Exception)
+	RevokeUserSession(ctx *ctx.Context, userID uint64, sessionID uint64) *exception.Exception
+}
+
+type UserSessionServiceImpl struct {
+	repo   repo.Repo
+	jwt    *jwt.JWT
+	config *config.SessionConfig
+	logger *zap.Logger
+}
+
+func NewUserSessionService(repo repo.Repo, jwt *jwt.JWT, config *config.SessionConfig, logger *zap.Logger) UserSessionService {
+	return &UserSessionServiceImpl{
+		repo:   repo,
+		jwt:    jwt,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Create 登录成功后记录会话, 超出设备类型的并发上限时踢出最早的会话
+func (s *UserSessionServiceImpl) Create(ctx *ctx.Context, userID uint64, pair *jwt.TokenPair) *exception.Exception {
+	deviceType := ctx.GetDeviceType()
+	if limit := s.config.MaxSessions.Get(deviceType); limit > 0 {
+		sessions, err := s.repo.UserSession().GetMany(ctx.Ctx,
+			repo.Where("user_id = ? AND device_type = ? AND expires_at > ?", userID, deviceType, time.Now()),
+			repo.Order("created_at ASC"),
+		)
+		if err != nil {
+			return exception.InternalServerError.Append(err.Error())
+		}
+		for i := 0; i <= len(sessions)-limit; i++ {
+			if exc := s.revoke(ctx, sessions[i]); exc != nil {
+				return exc
+			}
+		}
+	}
+	now := time.Now()
+	session := &model.UserSession{
+		UserID:     userID,
+		TokenID:    pair.FamilyID,
+		DeviceType: deviceType,
+		DeviceID:   ctx.GetDeviceID(),
+		DeviceName: ctx.GetDeviceName(),
+		DeviceInfo: ctx.GetDeviceInfo(),
+		UserAgent:  ctx.GetUserAgent(),
+		IP:         ctx.GetClientIP(),
+		LastSeenAt: &now,
+		ExpiresAt:  &pair.RefreshTokenExpiresAt,
+	}
+	if err := s.repo.UserSession().Create(ctx.Ctx, session); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+// Touch 刷新令牌时更新会话的最后活跃时间
+func (s *UserSessionServiceImpl) Touch(ctx *ctx.Context, pair *jwt.TokenPair) *exception.Exception {
+	now := time.Now()
+	err := s.repo.UserSession().UpdateByOptsAndNonZeroFields(ctx.Ctx, repo.Where("token_id = ?", pair.FamilyID), &model.UserSession{
+		IP:         ctx.GetClientIP(),
+		LastSeenAt: &now,
+		ExpiresAt:  &pair.RefreshTokenExpiresAt,
+	})
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserSessionServiceImpl) RemoveByTokenID(ctx *ctx.Context, tokenID string) *exception.Exception {
+	session, err := s.repo.UserSession().GetOne(ctx.Ctx, repo.Where("token_id = ?", tokenID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if session == nil {
+		return nil
+	}
+	if err := s.repo.UserSession().SoftDelete(ctx.Ctx, session.ID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserSessionServiceImpl) RemoveAllByUserID(ctx *ctx.Context, userID uint64) *exception.Exception {
+	sessions, err := s.repo.UserSession().GetMany(ctx.Ctx, repo.Where("user_id = ?", userID))
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if len(sessions) == 0 {
+		return nil
+	}
+	ids := make([]uint64, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	if err := s.repo.UserSession().SoftDeleteByIDs(ctx.Ctx, ids); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func (s *UserSessionServiceImpl) GetMySessions(ctx *ctx.Context) ([]*dto.UserSessionResDto, *exception.Exception) {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	return s.GetUserSessions(ctx, userID)
+}
+
+func (s *UserSessionServiceImpl) RevokeMySession(ctx *ctx.Context, sessionID uint64) *exception.Exception {
+	userID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return exc
+	}
+	return s.RevokeUserSession(ctx, userID, sessionID)
+}
+
+func (s *UserSessionServiceImpl) GetUserSessions(ctx *ctx.Context, userID uint64) ([]*dto.UserSessionResDto, *exception.Exception) {
+	sessions, err := s.repo.UserSession().GetMany(ctx.Ctx,
+		repo.Where("user_id = ? AND expires_at > ?", userID, time.Now()),
+		repo.Order("last_seen_at DESC"),
+	)
+	if err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	var currentTokenID string
+	if claims, ok := jwt.GetClaims(ctx.Gtx); ok {
+		currentTokenID = claims.FamilyID
+	}
+	res := make([]*dto.UserSessionResDto, 0, len(sessions))
+	for _, session := range sessions {
+		// 令牌家族已被撤销 (如刷新令牌重放) 的会话顺便清理
+		revoked, err := s.jwt.IsFamilyRevoked(ctx.Ctx, session.TokenID)
+		if err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		if revoked {
+			if err := s.repo.UserSession().SoftDelete(ctx.Ctx, session.ID); err != nil {
+				s.logger.Warn("delete revoked session failed", zap.Uint64("id", session.ID), zap.Error(err))
+			}
+			continue
+		}
+		res = append(res, &dto.UserSessionResDto{
+			ID:         session.ID,
+			DeviceType: session.DeviceType.String(),
+			DeviceID:   session.DeviceID,
+			DeviceName: session.DeviceName,
+			DeviceInfo: session.DeviceInfo,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  formatTime(session.CreatedAt),
+			LastSeenAt: formatTime(session.LastSeenAt),
+			ExpiresAt:  formatTime(session.ExpiresAt),
+			IsCurrent:  session.TokenID == currentTokenID,
+		})
+	}
+	return res, nil
+}
+
+func (s *UserSessionServiceImpl) RevokeUserSession(ctx *ctx.Context, userID uint64, sessionID uint64) *exception.Exception {
+	session, err := s.repo.UserSession().GetOne(ctx.Ctx, repo.Where("id = ? AND user_id = ?", sessionID, userID))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if session == nil {
+		return exception.UserSessionNotFound
+	}
+	return s.revoke(ctx, session)
+}
+
+// revoke 撤销会话对应的令牌家族并删除会话
+func (s *UserSessionServiceImpl) revoke(ctx *ctx.Context, session *model.UserSession) *exception.Exception {
+	if err := s.jwt.RevokeFamily(ctx.Ctx, session.TokenID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.repo.UserSession().SoftDelete(ctx.Ctx, session.ID); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
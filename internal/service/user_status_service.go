@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/dto"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserStatusService interface {
+	GetStatus(ctx *ctx.Context, userID uint64) (*dto.UserStatusResDto, *exception.Exception)
+	SetStatus(ctx *ctx.Context, userID uint64, params dto.UserSetStatusReqDto) (*dto.UserStatusResDto, *exception.Exception)
+	Check(ctx *ctx.Context, user *model.User) *exception.Exception
+	CheckCanChange(ctx *ctx.Context, user *model.User) *exception.Exception
+	LoadStatus(ctx context.Context, uniCode string) (enum.UserStatus, *time.Time, error)
+}
+
+type UserStatusServiceImpl struct {
+	repo     repo.Repo
+	jwt      *jwt.JWT
+	session  UserSessionService
+	userRole UserRoleService
+	audit    AuditService
+	logger   *zap.Logger
+}
+
+func NewUserStatusService(repo repo.Repo, jwt *jwt.JWT, session UserSessionService, userRole UserRoleService, audit AuditService, logger *zap.Logger) UserStatusService {
+	return &UserStatusServiceImpl{
+		repo:     repo,
+		jwt:      jwt,
+		session:  session,
+		userRole: userRole,
+		audit:    audit,
+		logger:   logger,
+	}
+}
+
+func (s *UserStatusServiceImpl) GetStatus(ctx *ctx.Context, userID uint64) (*dto.UserStatusResDto, *exception.Exception) {
+	user, exc := s.getUser(ctx, userID)
+	if exc != nil {
+		return nil, exc
+	}
+	if exc := s.liftExpired(ctx, user); exc != nil {
+		return nil, exc
+	}
+	return toUserStatusResDto(user), nil
+}
+
+// SetStatus 管理员修改账号状态, 封禁和停用会使已签发的令牌全部失效
+func (s *UserStatusServiceImpl) SetStatus(ctx *ctx.Context, userID uint64, params dto.UserSetStatusReqDto) (*dto.UserStatusResDto, *exception.Exception) {
+	operatorID, exc := ctx.GetUserID(s.repo)
+	if exc != nil {
+		return nil, exc
+	}
+	if operatorID == userID {
+		return nil, exception.UserStatusChangeForbidden
+	}
+	user, exc := s.getUser(ctx, userID)
+	if exc != nil {
+		return nil, exc
+	}
+	if exc := s.CheckCanChange(ctx, user); exc != nil {
+		return nil, exc
+	}
+	status := enum.UserStatus(params.Status)
+	var until *time.Time
+	if status == enum.UserStatusSuspended {
+		t, err := time.Parse(time.RFC3339, params.Until)
+		if err != nil || !t.After(time.Now()) {
+			return nil, exception.InvalidParam.Append("until must be in the future")
+		}
+		until = &t
+	}
+	now := time.Now()
+	updates := map[string]any{
+		"status":        status,
+		"status_reason": params.Reason,
+		"status_until":  until,
+		"status_set_by": operatorID,
+		"status_set_at": now,
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, updates); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	before := toUserStatusResDto(user)
+	user.Status, user.StatusReason, user.StatusUntil, user.StatusSetBy, user.StatusSetAt = status, params.Reason, until, &operatorID, &now
+	s.audit.Record(ctx, AuditEntry{
+		Action:     AuditActionUserStatusSet,
+		TargetType: enum.AuditTargetTypeUser,
+		TargetID:   strconv.FormatUint(user.ID, 10),
+		Before:     before,
+		After:      toUserStatusResDto(user),
+	})
+
+	if status == enum.UserStatusActive {
+		if err := s.jwt.UnblockUser(ctx.Ctx, user.UniCode); err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		return toUserStatusResDto(user), nil
+	}
+	if err := s.jwt.BlockUser(ctx.Ctx, user.UniCode, status, until); err != nil {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	// 暂停到期后可以继续使用原来的登录, 封禁和停用则需要重新登录
+	if status != enum.UserStatusSuspended {
+		if err := s.jwt.RevokeUserTokens(ctx.Ctx, user.UniCode); err != nil {
+			return nil, exception.InternalServerError.Append(err.Error())
+		}
+		if exc := s.session.RemoveAllByUserID(ctx, user.ID); exc != nil {
+			return nil, exc
+		}
+	}
+	return toUserStatusResDto(user), nil
+}
+
+// Check 登录和 API 密钥认证时检查账号状态, 已到期的暂停会被解除
+func (s *UserStatusServiceImpl) Check(ctx *ctx.Context, user *model.User) *exception.Exception {
+	if exc := s.liftExpired(ctx, user); exc != nil {
+		return exc
+	}
+	return exception.UserStatusException(user.Status)
+}
+
+// liftExpired 暂停到期时恢复为 active
+func (s *UserStatusServiceImpl) liftExpired(ctx *ctx.Context, user *model.User) *exception.Exception {
+	if user.Status != enum.UserStatusSuspended || user.StatusUntil == nil || user.StatusUntil.After(time.Now()) {
+		return nil
+	}
+	updates := map[string]any{
+		"status":        enum.UserStatusActive,
+		"status_reason": "",
+		"status_until":  nil,
+	}
+	if err := s.repo.User().UpdateByMap(ctx.Ctx, user.ID, updates); err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if err := s.jwt.UnblockUser(ctx.Ctx, user.UniCode); err != nil {
+		s.logger.Warn("unblock user failed", zap.String("uniCode", user.UniCode), zap.Error(err))
+	}
+	user.Status, user.StatusReason, user.StatusUntil = enum.UserStatusActive, "", nil
+	return nil
+}
+
+// LoadStatus 供 JWT 在 Redis 没有状态标记时读取数据库, 已删除的用户视为停用
+func (s *UserStatusServiceImpl) LoadStatus(ctx context.Context, uniCode string) (enum.UserStatus, *time.Time, error) {
+	// 结果会写入 Redis 标记, 从主库读取
+	user, err := s.repo.User().GetByUniCode(repo.WithPrimary(ctx), uniCode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return enum.UserStatusDeactivated, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return user.Status, user.StatusUntil, nil
+}
+
+// CheckCanChange 只有超级管理员可以修改管理员的状态或删除管理员
+func (s *UserStatusServiceImpl) CheckCanChange(ctx *ctx.Context, user *model.User) *exception.Exception {
+	roles, exc := s.userRole.GetEffectiveRolesCodeByUniCode(ctx, user.UniCode)
+	if exc != nil {
+		return exc
+	}
+	if !slices.Contains(roles, enum.RoleCodeAdmin) && !slices.Contains(roles, enum.RoleCodeSuperAdmin) {
+		return nil
+	}
+	uniCode, exc := ctx.GetUserUniCode()
+	if exc != nil {
+		return exc
+	}
+	operatorRoles, exc := s.userRole.GetEffectiveRolesCodeByUniCode(ctx, uniCode)
+	if exc != nil {
+		return exc
+	}
+	if !slices.Contains(operatorRoles, enum.RoleCodeSuperAdmin) {
+		return exception.UserStatusChangeForbidden
+	}
+	return nil
+}
+
+func (s *UserStatusServiceImpl) getUser(ctx *ctx.Context, userID uint64) (*model.User, *exception.Exception) {
+	user, err := s.repo.User().GetByID(ctx.Ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.InternalServerError.Append(err.Error())
+	}
+	if user == nil {
+		return nil, exception.UserNotFound
+	}
+	return user, nil
+}
+
+func toUserStatusResDto(user *model.User) *dto.UserStatusResDto {
+	return &dto.UserStatusResDto{
+		Status: user.Status.String(),
+		Reason: user.StatusReason,
+		Until:  formatTime(user.StatusUntil),
+		SetBy:  user.StatusSetBy,
+		SetAt:  formatTime(user.StatusSetAt),
+	}
+}
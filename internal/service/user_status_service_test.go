@@ -0,0 +1,70 @@
+package service
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/pkg/jwt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestUserBlockFallsBackToDatabase(t *testing.T) {
+	db := newTestDB(t, &model.Permission{}, &model.UserRole{}, &model.User{})
+	rds, server := newTestRedis(t)
+	expires := config.MultiTokenExpireConfig{Default: time.Hour}
+	j, err := jwt.NewJWT(&config.JWTConfig{Issuer: "test", TokenSecret: "test-secret", AccessTokenExpires: expires, RefreshTokenExpires: expires}, rds, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewUserStatusService(repo.NewRepo(db, zap.NewNop()), j, nil, nil, nil, zap.NewNop())
+	j.SetUserStatusLoader(s.LoadStatus)
+
+	expired := time.Now().Add(-time.Minute)
+	users := []*model.User{
+		{UniCode: "active", Status: enum.UserStatusActive},
+		{UniCode: "banned", Status: enum.UserStatusBanned},
+		{UniCode: "expired", Status: enum.UserStatusSuspended, StatusUntil: &expired},
+	}
+	for _, user := range users {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Redis 中没有任何标记, 例如被清空后
+	for uniCode, want := range map[string]enum.UserStatus{
+		"active":  "",
+		"banned":  enum.UserStatusBanned,
+		"expired": "",
+		"deleted": enum.UserStatusDeactivated,
+	} {
+		status, err := j.GetUserBlock(t.Context(), uniCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != want {
+			t.Fatalf("%s: status = %q, want %q", uniCode, status, want)
+		}
+	}
+	if ttl := server.TTL(constant.RedisKeyOfUserBlocked("banned")); ttl <= 0 || ttl > constant.REDIS_EXPIRE_OF_USER_BLOCKED {
+		t.Fatalf("loaded status ttl = %v", ttl)
+	}
+
+	// 缓存的状态不会覆盖管理员之后写入的标记
+	if err := j.BlockUser(t.Context(), "active", enum.UserStatusBanned, nil); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := j.GetUserBlock(t.Context(), "active"); err != nil || status != enum.UserStatusBanned {
+		t.Fatalf("status after ban = %q, %v", status, err)
+	}
+
+	// Redis 不可用时返回错误, 由调用方拒绝请求
+	server.Close()
+	if _, err := j.GetUserBlock(t.Context(), "banned"); err == nil {
+		t.Fatal("redis failure was treated as not blocked")
+	}
+}
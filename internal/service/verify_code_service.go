@@ -0,0 +1,156 @@
+package service
+
+import (
+	"crypto/subtle"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/i18n"
+	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/sender"
+	"go-server-starter/pkg/utils"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type VerifyCodeService interface {
+	Send(ctx *ctx.Context, scene enum.VerifyCodeScene, channel enum.VerifyCodeChannel, target string) *exception.Exception
+	Verify(ctx *ctx.Context, scene enum.VerifyCodeScene, channel enum.VerifyCodeChannel, target string, code string) *exception.Exception
+}
+
+type VerifyCodeServiceImpl struct {
+	config *config.VerifyCodeConfig
+	redis  *redis.Redis
+	sender sender.Sender
+	logger *zap.Logger
+}
+
+func NewVerifyCodeService(config *config.VerifyCodeConfig, redis *redis.Redis, sender sender.Sender, logger *zap.Logger) VerifyCodeService {
+	return &VerifyCodeServiceImpl{
+		config: config,
+		redis:  redis,
+		sender: sender,
+		logger: logger,
+	}
+}
+
+// MobileTarget 手机号验证码的发送目标
+func MobileTarget(countryCode string, mobile string) string {
+	return countryCode + "-" + mobile
+}
+
+func (s *VerifyCodeServiceImpl) Send(ctx *ctx.Context, scene enum.VerifyCodeScene, channel enum.VerifyCodeChannel, target string) *exception.Exception {
+	// 同一目标重发间隔
+	intervalKey := constant.RedisKeyOfVerifyCodeInterval(channel.String(), target)
+	ok, err := s.redis.SetNX(ctx.Ctx, intervalKey, 1, s.config.ResendInterval).Result()
+	if err != nil {
+		s.logger.Error("set verify code interval failed", zap.String("target", target), zap.Error(err))
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if !ok {
+		return exception.VerifyCodeSendTooFrequently
+	}
+	// 同一目标每天发送次数
+	if exc := s.incrAndCheck(ctx, constant.RedisKeyOfVerifyCodeTargetCount(channel.String(), target), constant.REDIS_EXPIRE_OF_VERIFY_CODE_TARGET, s.config.MaxSendPerTarget); exc != nil {
+		return exc
+	}
+	// 同一IP每小时发送次数
+	if exc := s.incrAndCheck(ctx, constant.RedisKeyOfVerifyCodeIPCount(ctx.GetClientIP()), constant.REDIS_EXPIRE_OF_VERIFY_CODE_IP, s.config.MaxSendPerIP); exc != nil {
+		return exc
+	}
+
+	code, err := utils.SecureRandomCode(s.config.Length, utils.Digits)
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	codeKey := constant.RedisKeyOfVerifyCode(scene.String(), channel.String(), target)
+	pipe := s.redis.TxPipeline()
+	pipe.Del(ctx.Ctx, codeKey)
+	pipe.HSet(ctx.Ctx, codeKey, "code", code, "attempts", 0, "used", 0)
+	pipe.Expire(ctx.Ctx, codeKey, s.config.Expire)
+	if _, err := pipe.Exec(ctx.Ctx); err != nil {
+		s.logger.Error("save verify code failed", zap.String("target", target), zap.Error(err))
+		return exception.InternalServerError.Append(err.Error())
+	}
+
+	locale := ctx.GetLocale()
+	msg := sender.Message{
+		To:      target,
+		Subject: i18n.MsgVerifyCodeSubject.T(locale),
+		Content: i18n.MsgVerifyCodeContent.T(locale, map[string]string{
+			"code":    code,
+			"minutes": strconv.Itoa(int(s.config.Expire.Minutes())),
+		}),
+	}
+	switch channel {
+	case enum.VerifyCodeChannelMobile:
+		msg.Channel = sender.ChannelSMS
+	case enum.VerifyCodeChannelEmail:
+		msg.Channel = sender.ChannelEmail
+	}
+	if err := s.sender.Send(ctx.Ctx, msg); err != nil {
+		s.logger.Error("send verify code failed", zap.String("channel", channel.String()), zap.String("target", target), zap.Error(err))
+		// 发送失败时清理验证码和重发间隔，允许立即重试
+		s.redis.Del(ctx.Ctx, codeKey, intervalKey)
+		return exception.VerifyCodeSendFailed
+	}
+	return nil
+}
+
+func (s *VerifyCodeServiceImpl) Verify(ctx *ctx.Context, scene enum.VerifyCodeScene, channel enum.VerifyCodeChannel, target string, code string) *exception.Exception {
+	codeKey := constant.RedisKeyOfVerifyCode(scene.String(), channel.String(), target)
+	data, err := s.redis.HGetAll(ctx.Ctx, codeKey).Result()
+	if err != nil {
+		s.logger.Error("get verify code failed", zap.String("target", target), zap.Error(err))
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if len(data) == 0 {
+		return exception.VerifyCodeNotFoundOrExpired
+	}
+	if data["used"] != "0" {
+		return exception.VerifyCodeUsed
+	}
+	if subtle.ConstantTimeCompare([]byte(data["code"]), []byte(code)) != 1 {
+		attempts, err := s.redis.HIncrBy(ctx.Ctx, codeKey, "attempts", 1).Result()
+		if err != nil {
+			return exception.InternalServerError.Append(err.Error())
+		}
+		if attempts >= int64(s.config.MaxAttempts) {
+			s.redis.Del(ctx.Ctx, codeKey)
+			return exception.VerifyCodeTooManyAttempts
+		}
+		if channel == enum.VerifyCodeChannelMobile {
+			return exception.UserMobileVerificationCodeIsIncorrect
+		}
+		return exception.UserEmailVerificationCodeIsIncorrect
+	}
+	// 原子标记为已使用，并发请求中只有一个能成功
+	used, err := s.redis.HIncrBy(ctx.Ctx, codeKey, "used", 1).Result()
+	if err != nil {
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if used > 1 {
+		return exception.VerifyCodeUsed
+	}
+	return nil
+}
+
+// incrAndCheck 计数并检查是否超过上限
+func (s *VerifyCodeServiceImpl) incrAndCheck(ctx *ctx.Context, key string, window time.Duration, limit int) *exception.Exception {
+	count, err := s.redis.Incr(ctx.Ctx, key).Result()
+	if err != nil {
+		s.logger.Error("incr verify code count failed", zap.String("key", key), zap.Error(err))
+		return exception.InternalServerError.Append(err.Error())
+	}
+	if count == 1 {
+		s.redis.Expire(ctx.Ctx, key, window)
+	}
+	if limit > 0 && count > int64(limit) {
+		return exception.VerifyCodeSendLimitExceeded
+	}
+	return nil
+}
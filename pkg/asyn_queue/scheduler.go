@@ -0,0 +1,50 @@
+package asyn_queue
+
+import (
+	"fmt"
+	"go-server-starter/internal/config"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+// Scheduler 定时入队任务, 多个实例同时运行时同一个任务会重复入队, 可以配合 asynq.Unique 去重
+type Scheduler struct {
+	*asynq.Scheduler
+	logger *zap.Logger
+}
+
+func NewScheduler(config config.AsynQConfig, logger *zap.Logger) *Scheduler {
+	scheduler := asynq.NewScheduler(
+		asynq.RedisClientOpt{
+			Addr:     fmt.Sprintf("%s:%d", config.RedisConfig.Host, config.RedisConfig.Port),
+			Password: config.RedisConfig.Password,
+			DB:       config.RedisConfig.DB,
+		},
+		&asynq.SchedulerOpts{
+			EnqueueErrorHandler: func(task *asynq.Task, opts []asynq.Option, err error) {
+				logger.Error("scheduled task enqueue failed", zap.String("type", task.Type()), zap.Error(err))
+			},
+		},
+	)
+	return &Scheduler{Scheduler: scheduler, logger: logger}
+}
+
+// Register 按 cron 表达式定时入队任务
+func (s *Scheduler) Register(cronspec string, task *asynq.Task, opts ...asynq.Option) error {
+	if _, err := s.Scheduler.Register(cronspec, task, opts...); err != nil {
+		return fmt.Errorf("register scheduled task %s: %w", task.Type(), err)
+	}
+	return nil
+}
+
+// Start 启动调度器
+func (s *Scheduler) Start() error {
+	return s.Scheduler.Start()
+}
+
+// Shutdown 关闭调度器
+func (s *Scheduler) Shutdown() {
+	s.Scheduler.Shutdown()
+	s.logger.Info("asynq scheduler stopped")
+}
@@ -1,10 +1,18 @@
 package auth
 
 import (
+	"bytes"
+	"encoding/json"
+	"go-server-starter/internal/constant"
 	"go-server-starter/internal/ctx"
 	"go-server-starter/internal/enum"
 	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
 	"go-server-starter/internal/service"
+	"go-server-starter/pkg/jwt"
+	"io"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -17,19 +25,190 @@ const (
 	RoleCheckTypeAny RoleCheckType = "any" // 任意一个角色符合
 )
 
+// API 密钥请求头
+const APIKeyHeader = "X-API-Key"
+
 type Auth interface {
+	JWT() gin.HandlerFunc
+	Authenticate() gin.HandlerFunc
 	RoleCheck(roleCheckType RoleCheckType, roles ...enum.RoleCode) gin.HandlerFunc
 	RoleCheckAny(roles ...enum.RoleCode) gin.HandlerFunc
 	RoleCheckAll(roles ...enum.RoleCode) gin.HandlerFunc
+	RequirePermission(permissions ...enum.PermissionCode) gin.HandlerFunc
+	NoImpersonation() gin.HandlerFunc
+	Audit(targetType enum.AuditTargetType) gin.HandlerFunc
 }
 
 type AuthImpl struct {
 	service service.Service
+	jwt     *jwt.JWT
 	logger  *zap.Logger
 }
 
-func NewAuth(service service.Service, logger *zap.Logger) Auth {
-	return &AuthImpl{service: service, logger: logger}
+func NewAuth(service service.Service, jwt *jwt.JWT, logger *zap.Logger) Auth {
+	return &AuthImpl{service: service, jwt: jwt, logger: logger}
+}
+
+// JWT 只允许 JWT 认证, 模拟登录的请求处理完成后记录审计日志
+func (a *AuthImpl) JWT() gin.HandlerFunc {
+	jwtHandler := a.jwt.JWT()
+	return func(c *gin.Context) {
+		jwtHandler(c)
+		if claims, ok := jwt.GetClaims(c); ok && claims.Act != nil {
+			a.service.Impersonation().RecordRequest(ctx.FromGinCtx(c))
+		}
+	}
+}
+
+// Authenticate 携带 X-API-Key 时使用 API 密钥认证, 否则使用 JWT 认证, 两者都会设置用户唯一码
+func (a *AuthImpl) Authenticate() gin.HandlerFunc {
+	jwtHandler := a.JWT()
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			jwtHandler(c)
+			return
+		}
+		var ctx = ctx.FromGinCtx(c)
+		key, exc := a.service.APIKey().Authenticate(ctx, rawKey)
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		ctx.SetUserUniCode(key.User.UniCode)
+		c.Set(constant.CTX_KEY_OF_API_KEY, key)
+		c.Next()
+	}
+}
+
+// RequirePermission 要求用户拥有所有指定权限, API 密钥请求还要求密钥的权限范围包含这些权限
+func (a *AuthImpl) RequirePermission(requiredPermissions ...enum.PermissionCode) gin.HandlerFunc {
+	scopes := make([]string, len(requiredPermissions))
+	for i, permission := range requiredPermissions {
+		scopes[i] = permission.String()
+	}
+	return func(c *gin.Context) {
+		var ctx = ctx.FromGinCtx(c)
+		uniCode, exc := ctx.GetUserUniCode()
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		userPermissions, exc := a.service.Permission().GetCachedPermissionsCodeByUniCode(ctx, uniCode)
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		granted := make(map[enum.PermissionCode]struct{}, len(userPermissions))
+		for _, permission := range userPermissions {
+			granted[permission] = struct{}{}
+		}
+		for _, required := range requiredPermissions {
+			if _, ok := granted[required]; !ok {
+				ctx.ToError(exception.Forbidden)
+				return
+			}
+		}
+		if key, ok := GetAPIKey(c); ok && !hasScopes(key, scopes) {
+			ctx.ToError(exception.APIKeyScopeDenied)
+			return
+		}
+		userRoles, exc := a.service.UserRole().GetEffectiveRolesCodeByUniCode(ctx, uniCode)
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		if exc := a.service.MFA().CheckRequired(ctx, uniCode, userRoles); exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		c.Next()
+	}
+}
+
+// NoImpersonation 模拟登录的令牌不能进行修改凭证等敏感操作
+func (a *AuthImpl) NoImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := jwt.GetImpersonator(c); ok {
+			ctx.FromGinCtx(c).ToError(exception.ImpersonationForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Audit 自动记录管理接口成功的写操作, 路径参数 id 为操作对象, 请求体中的敏感字段会被隐藏
+// 已在 service 中显式记录的请求不会重复记录
+func (a *AuthImpl) Audit(targetType enum.AuditTargetType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(io.LimitReader(c.Request.Body, auditMaxBodySize))
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		}
+		c.Next()
+		if c.GetBool(constant.CTX_KEY_OF_AUDITED) || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		var after map[string]any
+		if err := json.Unmarshal(body, &after); err == nil {
+			redact(after)
+		}
+		a.service.Audit().Record(ctx.FromGinCtx(c), service.AuditEntry{
+			Action:     c.Request.Method + " " + c.FullPath(),
+			TargetType: targetType,
+			TargetID:   c.Param("id"),
+			After:      after,
+		})
+	}
+}
+
+// 自动审计时最多读取的请求体大小
+const auditMaxBodySize = 64 << 10
+
+// redact 隐藏请求体中的密码, 密钥和验证码等字段
+func redact(data map[string]any) {
+	for key, value := range data {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "password") || strings.Contains(lower, "secret") || strings.Contains(lower, "token") || lower == "code" {
+			data[key] = "***"
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			redact(nested)
+		}
+	}
+}
+
+// hasScopes 检查密钥是否拥有所有要求的权限范围, "*" 表示全部
+func hasScopes(key *model.APIKey, scopes []string) bool {
+	granted := make(map[string]struct{}, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		granted[scope] = struct{}{}
+	}
+	if _, ok := granted[service.APIKeyScopeAll]; ok {
+		return true
+	}
+	for _, scope := range scopes {
+		if _, ok := granted[scope]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// GetAPIKey 获取通过 API 密钥认证的请求所使用的密钥
+func GetAPIKey(c *gin.Context) (*model.APIKey, bool) {
+	value, ok := c.Get(constant.CTX_KEY_OF_API_KEY)
+	if !ok {
+		return nil, false
+	}
+	key, ok := value.(*model.APIKey)
+	return key, ok
 }
 
 func (a *AuthImpl) RoleCheck(roleCheckType RoleCheckType, requiredRoles ...enum.RoleCode) gin.HandlerFunc {
@@ -40,7 +219,7 @@ func (a *AuthImpl) RoleCheck(roleCheckType RoleCheckType, requiredRoles ...enum.
 			ctx.ToError(exc)
 			return
 		}
-		userRoles, exc := a.service.UserRole().GetCachedRolesCodeByUniCode(ctx, uniCode)
+		userRoles, exc := a.service.UserRole().GetEffectiveRolesCodeByUniCode(ctx, uniCode)
 		if exc != nil {
 			ctx.ToError(exc)
 			return
@@ -49,6 +228,11 @@ func (a *AuthImpl) RoleCheck(roleCheckType RoleCheckType, requiredRoles ...enum.
 			ctx.ToError(exception.Forbidden)
 			return
 		}
+		// 角色要求两步验证时, 未开启的用户不能访问
+		if exc := a.service.MFA().CheckRequired(ctx, uniCode, userRoles); exc != nil {
+			ctx.ToError(exc)
+			return
+		}
 		c.Next()
 	}
 }
@@ -15,9 +15,14 @@ import (
 
 type DB struct {
 	*gorm.DB
+	cfg       config.DatabaseConfig
+	loc       *time.Location
+	replicas  []*replica
+	stopCheck context.CancelFunc
 }
 
 func (db *DB) Close() error {
+	db.closeReplicas()
 	sqlDB, err := db.DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get sql db: %w", err)
@@ -104,7 +109,7 @@ func NewDB(cfg config.DatabaseConfig, logger logger.Interface, gormConfig *gorm.
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	return &DB{db}, nil
+	return &DB{DB: db, cfg: cfg, loc: loc}, nil
 }
 
 func (db *DB) Ping(ctx context.Context) error {
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"math/rand/v2"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replica 只读副本, healthy 由健康检查维护
+type replica struct {
+	addr    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// UseReplicas 注册配置中的只读副本, 之后查询走健康的副本, 写入和事务走主库, 没有配置副本时不做处理
+// 迁移和初始化数据需要读到最新的结构, 应在注册之前完成
+//
+// 主库也放在副本列表的最后, 这样只有一个副本时也会经过 replicaPolicy, 所有副本都不可用时回退到主库
+// 注册时不检查副本是否可用, 由 StartReplicaCheck 的首次检查暂停不可用的副本
+func (d *DB) UseReplicas() error {
+	if len(d.cfg.Replicas) == 0 {
+		return nil
+	}
+	cfg, loc := d.cfg, d.loc
+	primary, err := d.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql db: %w", err)
+	}
+	dialectors := make([]gorm.Dialector, 0, len(cfg.Replicas)+1)
+	for _, rc := range cfg.Replicas {
+		r, err := openReplica(cfg, rc, loc)
+		if err != nil {
+			d.closeReplicas()
+			return err
+		}
+		d.replicas = append(d.replicas, r)
+		dialectors = append(dialectors, mysql.New(mysql.Config{Conn: r.db, SkipInitializeWithVersion: true, DefaultStringSize: 256}))
+	}
+	dialectors = append(dialectors, mysql.New(mysql.Config{Conn: primary, SkipInitializeWithVersion: true, DefaultStringSize: 256}))
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   &replicaPolicy{replicas: d.replicas},
+	})
+	if err := d.DB.Use(resolver); err != nil {
+		d.closeReplicas()
+		return fmt.Errorf("failed to register db resolver: %w", err)
+	}
+	return nil
+}
+
+func openReplica(cfg config.DatabaseConfig, rc config.ReplicaConfig, loc *time.Location) (*replica, error) {
+	username, password := rc.Username, rc.Password
+	if username == "" {
+		username, password = cfg.Username, cfg.Password
+	}
+	port := rc.Port
+	if port == 0 {
+		port = cfg.Port
+	}
+	dsn := &mysqlDriver.Config{
+		User:      username,
+		Passwd:    password,
+		Net:       "tcp",
+		Addr:      fmt.Sprintf("%s:%d", rc.Host, port),
+		DBName:    cfg.Name,
+		ParseTime: cfg.ParseTime,
+		Loc:       loc,
+		Params:    map[string]string{"charset": cfg.Charset},
+	}
+	db, err := sql.Open("mysql", dsn.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replica %s: %w", dsn.Addr, err)
+	}
+	maxIdleConns, maxOpenConns, connMaxLifetime := rc.MaxIdleConns, rc.MaxOpenConns, rc.ConnMaxLifetime
+	if maxOpenConns <= 0 {
+		maxOpenConns = cfg.MaxOpenConns
+	}
+	if maxIdleConns <= 0 {
+		maxIdleConns = min(cfg.MaxIdleConns, maxOpenConns)
+	}
+	if connMaxLifetime <= 0 {
+		connMaxLifetime = cfg.ConnMaxLifetime
+	}
+	if maxIdleConns > maxOpenConns {
+		db.Close()
+		return nil, fmt.Errorf("replica %s: MaxIdleConns cannot be greater than MaxOpenConns", dsn.Addr)
+	}
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
+	r := &replica{addr: dsn.Addr, db: db}
+	r.healthy.Store(true)
+	return r, nil
+}
+
+// replicaPolicy 在健康的副本中随机选择, 都不可用时使用最后一个连接池 (主库)
+type replicaPolicy struct {
+	replicas []*replica
+}
+
+func (p *replicaPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	healthy := make([]gorm.ConnPool, 0, len(connPools))
+	for _, pool := range connPools {
+		for _, r := range p.replicas {
+			if pool == gorm.ConnPool(r.db) && r.healthy.Load() {
+				healthy = append(healthy, pool)
+			}
+		}
+	}
+	if len(healthy) == 0 {
+		return connPools[len(connPools)-1]
+	}
+	return healthy[rand.IntN(len(healthy))]
+}
+
+// StartReplicaCheck 定期检查副本的连通性和复制延迟, 不可用或延迟过大的副本暂停使用, 恢复后重新启用
+func (d *DB) StartReplicaCheck(maxLag time.Duration, interval time.Duration, logger *zap.Logger) {
+	if len(d.replicas) == 0 || interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopCheck = cancel
+	d.checkReplicas(ctx, maxLag, logger)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.checkReplicas(ctx, maxLag, logger)
+			}
+		}
+	}()
+}
+
+func (d *DB) checkReplicas(ctx context.Context, maxLag time.Duration, logger *zap.Logger) {
+	for _, r := range d.replicas {
+		lag, err := replicationLag(ctx, r.db)
+		if err == nil && lag > maxLag {
+			err = fmt.Errorf("replication lag %s exceeds %s", lag, maxLag)
+		}
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy {
+			continue
+		}
+		if healthy {
+			logger.Info("replica is back in use", zap.String("addr", r.addr), zap.Duration("lag", lag))
+		} else {
+			logger.Warn("replica is out of use", zap.String("addr", r.addr), zap.Error(err))
+		}
+	}
+}
+
+// replicationLag 副本的复制延迟, 不是副本 (没有复制状态) 时为 0, 复制中断时返回错误
+func replicationLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	// MySQL 8.0.22 之前只支持 SHOW SLAVE STATUS
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		if rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+			continue
+		}
+		if values[i] == nil {
+			return 0, errors.New("replication is not running")
+		}
+		seconds, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, errors.New("replication lag is not reported")
+}
+
+func (d *DB) closeReplicas() {
+	if d.stopCheck != nil {
+		d.stopCheck()
+	}
+	for _, r := range d.replicas {
+		r.db.Close()
+	}
+}
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/enum"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// UserBlockedError 账号状态不是 active 时刷新令牌返回的错误
+type UserBlockedError struct {
+	Status enum.UserStatus
+}
+
+func (e *UserBlockedError) Error() string {
+	return fmt.Sprintf("user is %s", e.Status)
+}
+
+// UserStatusLoader 从数据库读取账号状态, Redis 中没有标记时使用
+type UserStatusLoader func(ctx context.Context, uniCode string) (enum.UserStatus, *time.Time, error)
+
+// SetUserStatusLoader 设置账号状态的数据库回源, 未设置时没有标记视为 active
+func (j *JWT) SetUserStatusLoader(loader UserStatusLoader) {
+	j.statusLoader = loader
+}
+
+// BlockUser 标记账号状态, until 不为空时标记到期自动失效 (暂停到期)
+func (j *JWT) BlockUser(ctx context.Context, uniCode string, status enum.UserStatus, until *time.Time) error {
+	var ttl time.Duration
+	if until != nil {
+		ttl = time.Until(*until)
+		if ttl <= 0 {
+			return j.UnblockUser(ctx, uniCode)
+		}
+	}
+	return j.redis.Set(ctx, constant.RedisKeyOfUserBlocked(uniCode), status.String(), ttl).Err()
+}
+
+// UnblockUser 清除账号状态标记
+func (j *JWT) UnblockUser(ctx context.Context, uniCode string) error {
+	return j.redis.Del(ctx, constant.RedisKeyOfUserBlocked(uniCode)).Err()
+}
+
+// GetUserBlock 获取账号状态, active 时返回空
+//
+// 标记不存在 (Redis 被清空或切换) 时从数据库读取并缓存, Redis 或数据库出错时返回错误, 调用方应拒绝请求.
+func (j *JWT) GetUserBlock(ctx context.Context, uniCode string) (enum.UserStatus, error) {
+	value, err := j.redis.Get(ctx, constant.RedisKeyOfUserBlocked(uniCode)).Result()
+	if err == goredis.Nil {
+		return j.loadUserBlock(ctx, uniCode)
+	}
+	if err != nil {
+		return "", err
+	}
+	if value == enum.UserStatusActive.String() {
+		return "", nil
+	}
+	return enum.UserStatus(value), nil
+}
+
+// loadUserBlock 从数据库读取账号状态, 缓存时使用 SetNX 避免覆盖管理员同时写入的标记
+func (j *JWT) loadUserBlock(ctx context.Context, uniCode string) (enum.UserStatus, error) {
+	if j.statusLoader == nil {
+		return "", nil
+	}
+	status, until, err := j.statusLoader(ctx, uniCode)
+	if err != nil {
+		return "", err
+	}
+	ttl := constant.REDIS_EXPIRE_OF_USER_BLOCKED
+	if until != nil {
+		if remaining := time.Until(*until); remaining <= 0 {
+			status = enum.UserStatusActive
+		} else if remaining < ttl {
+			ttl = remaining
+		}
+	}
+	if err := j.redis.SetNX(ctx, constant.RedisKeyOfUserBlocked(uniCode), status.String(), ttl).Err(); err != nil {
+		return "", err
+	}
+	if status == enum.UserStatusActive {
+		return "", nil
+	}
+	return status, nil
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONWebKey RFC 7517 公钥格式
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS 所有可用于验证的公钥, 使用 HS256 时为空
+func (j *JWT) JWKS() *JSONWebKeySet {
+	set := &JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(j.keys.keys))}
+	for _, key := range j.keys.keys {
+		if !key.isAsymmetric() {
+			continue
+		}
+		jwk := JSONWebKey{Kid: key.kid, Alg: key.method.Alg(), Use: "sig"}
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (publicKey.Curve.Params().BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = publicKey.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(a, b int) bool {
+		return set.Keys[a].Kid < set.Keys[b].Kid
+	})
+	return set
+}
+
+// JWKSHandler 发布公钥, 供其他服务验证令牌
+func (j *JWT) JWKSHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, j.JWKS())
+	}
+}
@@ -3,9 +3,13 @@ package jwt
 import (
 	"errors"
 	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
 	"go-server-starter/internal/ctx"
 	"go-server-starter/internal/enum"
 	"go-server-starter/internal/exception"
+	"go-server-starter/pkg/redis"
+	"go-server-starter/pkg/utils"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,21 +19,40 @@ import (
 )
 
 type CustomClaims struct {
-	UniCode string `json:"uniCode"` // 用户唯一码
+	UniCode    string          `json:"uniCode"`       // 用户唯一码
+	FamilyID   string          `json:"fid"`           // 令牌家族ID, 同一次登录签发的令牌共享
+	DeviceType enum.DeviceType `json:"deviceType"`    // 设备类型
+	Act        *ActClaim       `json:"act,omitempty"` // 模拟登录时的实际操作者 (RFC 8693)
+	Tenant     string          `json:"tid,omitempty"` // 令牌绑定的组织 Slug, 设置后不能访问其他组织
+	IssuedAtMs int64           `json:"iatMs"`         // 签发时间 (unix ms), iat 只精确到秒, 用于与令牌生效时间比较
 	jwt.RegisteredClaims
 }
 
+// ActClaim 模拟登录的管理员
+type ActClaim struct {
+	UniCode string `json:"sub"`
+}
+
 type JWT struct {
-	config *config.JWTConfig
-	logger *zap.Logger
+	config       *config.JWTConfig
+	keys         *keySet
+	redis        *redis.Redis
+	statusLoader UserStatusLoader
+	logger       *zap.Logger
 }
 
-func NewJWT(config *config.JWTConfig, logger *zap.Logger) *JWT {
+func NewJWT(config *config.JWTConfig, redis *redis.Redis, logger *zap.Logger) (*JWT, error) {
+	keys, err := loadKeySet(config)
+	if err != nil {
+		return nil, err
+	}
 	j := &JWT{
 		config: config,
+		keys:   keys,
+		redis:  redis,
 		logger: logger,
 	}
-	return j
+	return j, nil
 }
 
 func (j *JWT) JWT() gin.HandlerFunc {
@@ -45,28 +68,51 @@ func (j *JWT) JWT() gin.HandlerFunc {
 			ctx.ToError(exception.TokenInvalid.Append(err.Error()))
 			return
 		}
+		// 被暂停, 封禁或停用的账号
+		status, err := j.GetUserBlock(ctx.Ctx, claims.UniCode)
+		if err != nil {
+			j.logger.Error("check user blocked failed", zap.String("uniCode", claims.UniCode), zap.Error(err))
+			ctx.ToError(exception.InternalServerError.Append(err.Error()))
+			return
+		}
+		if exc := exception.UserStatusException(status); exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		revoked, err := j.IsTokenRevoked(ctx.Ctx, claims)
+		if err != nil {
+			j.logger.Error("check token revoked failed", zap.String("jti", claims.ID), zap.Error(err))
+			ctx.ToError(exception.InternalServerError.Append(err.Error()))
+			return
+		}
+		if revoked {
+			ctx.ToError(exception.TokenRevoked)
+			return
+		}
 		// 设置用户唯一码
 		ctx.SetUserUniCode(claims.UniCode)
-		// 判断是否需要刷新令牌
-		if j.isTokenNeedRefresh(ctx, claims.ExpiresAt.Time) {
-			// 刷新令牌
-			newToken, err := j.GenerateToken(claims.UniCode, ctx.GetDeviceType())
-			if err != nil {
-				ctx.ToError(exception.TokenGenerateFailed.Append(err.Error()))
-				return
-			}
-			c.Header("new-token", newToken)
+		c.Set(constant.CTX_KEY_OF_TOKEN_CLAIMS, claims)
+		if claims.Act != nil {
+			c.Set(constant.CTX_KEY_OF_IMPERSONATOR, claims.Act.UniCode)
 		}
 		c.Next()
 	}
 }
 
-func (j *JWT) isTokenNeedRefresh(ctx *ctx.Context, expiresAt time.Time) bool {
-	// 判断是否需要刷新令牌 还有多少时间过期
-	var remaining = time.Until(expiresAt)
-	expire := j.config.TokenExpires.Get(ctx.GetDeviceType())
-	// 如果剩余时间小于过期时间的1/3，则需要刷新令牌
-	return (remaining < expire/3) && (remaining > 0)
+// GetImpersonator 模拟登录时返回管理员的唯一码
+func GetImpersonator(c *gin.Context) (string, bool) {
+	uniCode := c.GetString(constant.CTX_KEY_OF_IMPERSONATOR)
+	return uniCode, uniCode != ""
+}
+
+// GetClaims 获取 JWT 中间件解析出的令牌声明
+func GetClaims(c *gin.Context) (*CustomClaims, bool) {
+	value, ok := c.Get(constant.CTX_KEY_OF_TOKEN_CLAIMS)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*CustomClaims)
+	return claims, ok
 }
 
 func (j *JWT) GetTokenFromGinContext(c *gin.Context) (string, error) {
@@ -88,29 +134,84 @@ func (j *JWT) GetTokenFromGinContext(c *gin.Context) (string, error) {
 	return token, nil
 }
 
-func (j *JWT) GenerateToken(uniCode string, deviceType enum.DeviceType) (string, error) {
-	expire := j.config.TokenExpires.Get(deviceType)
+// GenerateAccessToken 签发访问令牌
+func (j *JWT) GenerateAccessToken(uniCode string, familyID string, deviceType enum.DeviceType) (string, time.Time, error) {
+	return j.generateAccessToken(uniCode, familyID, deviceType, j.config.AccessTokenExpires.Get(deviceType), nil, "")
+}
+
+// GenerateImpersonationToken 签发模拟登录的访问令牌, 使用独立的令牌家族且没有刷新令牌
+func (j *JWT) GenerateImpersonationToken(uniCode string, impersonatorUniCode string, deviceType enum.DeviceType) (string, time.Time, error) {
+	familyID, err := utils.NewUUID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return j.generateAccessToken(uniCode, familyID, deviceType, j.config.ImpersonationTokenExpires, &ActClaim{UniCode: impersonatorUniCode}, "")
+}
+
+// GenerateTenantToken 签发绑定组织的访问令牌, 与当前令牌属于同一个令牌家族
+func (j *JWT) GenerateTenantToken(uniCode string, familyID string, deviceType enum.DeviceType, tenant string) (string, time.Time, error) {
+	return j.generateAccessToken(uniCode, familyID, deviceType, j.config.AccessTokenExpires.Get(deviceType), nil, tenant)
+}
+
+func (j *JWT) generateAccessToken(uniCode string, familyID string, deviceType enum.DeviceType, expire time.Duration, act *ActClaim, tenant string) (string, time.Time, error) {
 	now := time.Now()
+	expiresAt := now.Add(expire)
+	tokenID, err := utils.NewUUID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
 	claims := CustomClaims{
-		UniCode: uniCode,
+		UniCode:    uniCode,
+		FamilyID:   familyID,
+		DeviceType: deviceType,
+		Act:        act,
+		Tenant:     tenant,
+		IssuedAtMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			Issuer:    j.config.Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
-	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.config.TokenSecret))
+	if audience := j.config.Audience.Get(deviceType); audience != "" {
+		claims.Audience = jwt.ClaimStrings{audience}
+	}
+	key := j.keys.active
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.kid != "" {
+		token.Header["kid"] = key.kid
+	}
+	tokenStr, err := token.SignedString(key.privateKey)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
-	return tokenStr, nil
+	return tokenStr, expiresAt, nil
 }
 
+// ParseAndVerifyToken 严格校验令牌: kid 对应的算法, 签发者, 过期时间以及设备类型对应的受众
 func (j *JWT) ParseAndVerifyToken(tokenStr string) (*CustomClaims, error) {
+	var key *signingKey
 	var token, err = jwt.ParseWithClaims(tokenStr, &CustomClaims{}, func(token *jwt.Token) (any, error) {
-		return []byte(j.config.TokenSecret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		var ok bool
+		key, ok = j.keys.keys[kid]
+		if !ok {
+			return nil, errors.New("unknown kid")
+		}
+		// 算法由密钥决定, 不信任令牌头部
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.publicKey, nil
+	},
+		jwt.WithValidMethods(j.keys.methods),
+		jwt.WithIssuer(j.config.Issuer),
+		jwt.WithLeeway(j.config.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	if err != nil || token == nil || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
@@ -118,5 +219,8 @@ func (j *JWT) ParseAndVerifyToken(tokenStr string) (*CustomClaims, error) {
 	if !ok {
 		return nil, errors.New("invalid token claims type")
 	}
+	if audience := j.config.Audience.Get(claims.DeviceType); audience != "" && !slices.Contains(claims.Audience, audience) {
+		return nil, errors.New("invalid token audience")
+	}
 	return claims, nil
 }
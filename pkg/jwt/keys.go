@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"os"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey 一个带 kid 的密钥, 算法与密钥绑定
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey crypto.PrivateKey // 仅用于验证的密钥为 nil
+	publicKey  crypto.PublicKey
+}
+
+// keySet 签名密钥和所有可用于验证的密钥
+type keySet struct {
+	active  *signingKey
+	keys    map[string]*signingKey
+	methods []string // 已配置密钥的算法, 其他算法一律拒绝
+}
+
+// loadKeySet 从配置加载密钥, 未配置非对称密钥时使用 TokenSecret 做 HS256 签名
+func loadKeySet(cfg *config.JWTConfig) (*keySet, error) {
+	if len(cfg.Keys) == 0 {
+		if cfg.TokenSecret == "" {
+			return nil, errors.New("jwt: tokenSecret or keys must be configured")
+		}
+		key := &signingKey{
+			method:     jwt.SigningMethodHS256,
+			privateKey: []byte(cfg.TokenSecret),
+			publicKey:  []byte(cfg.TokenSecret),
+		}
+		return &keySet{active: key, keys: map[string]*signingKey{"": key}, methods: []string{key.method.Alg()}}, nil
+	}
+	set := &keySet{keys: make(map[string]*signingKey, len(cfg.Keys))}
+	for _, keyConfig := range cfg.Keys {
+		if keyConfig.KID == "" {
+			return nil, errors.New("jwt: key kid is empty")
+		}
+		if _, ok := set.keys[keyConfig.KID]; ok {
+			return nil, fmt.Errorf("jwt: duplicate key kid %q", keyConfig.KID)
+		}
+		key, err := loadKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: load key %q: %w", keyConfig.KID, err)
+		}
+		set.keys[keyConfig.KID] = key
+		if !slices.Contains(set.methods, key.method.Alg()) {
+			set.methods = append(set.methods, key.method.Alg())
+		}
+	}
+	active, ok := set.keys[cfg.SigningKeyID]
+	if !ok {
+		return nil, fmt.Errorf("jwt: signing key %q not found in keys", cfg.SigningKeyID)
+	}
+	if active.privateKey == nil {
+		return nil, fmt.Errorf("jwt: signing key %q has no private key", cfg.SigningKeyID)
+	}
+	set.active = active
+	return set, nil
+}
+
+func loadKey(keyConfig config.JWTKeyConfig) (*signingKey, error) {
+	key := &signingKey{kid: keyConfig.KID}
+	switch keyConfig.Algorithm {
+	case "RS256":
+		key.method = jwt.SigningMethodRS256
+	case "ES256":
+		key.method = jwt.SigningMethodES256
+	case "EdDSA":
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", keyConfig.Algorithm)
+	}
+	if keyConfig.PrivateKeyFile != "" {
+		data, err := os.ReadFile(keyConfig.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := key.parsePrivateKey(data); err != nil {
+			return nil, err
+		}
+	}
+	if keyConfig.PublicKeyFile != "" {
+		data, err := os.ReadFile(keyConfig.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := key.parsePublicKey(data); err != nil {
+			return nil, err
+		}
+	}
+	if key.publicKey == nil {
+		return nil, errors.New("privateKeyFile or publicKeyFile must be configured")
+	}
+	return key, nil
+}
+
+// parsePrivateKey 解析私钥并推导公钥
+func (k *signingKey) parsePrivateKey(data []byte) error {
+	switch k.method {
+	case jwt.SigningMethodRS256:
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return err
+		}
+		k.privateKey, k.publicKey = privateKey, &privateKey.PublicKey
+	case jwt.SigningMethodES256:
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(data)
+		if err != nil {
+			return err
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return errors.New("ES256 requires a P-256 key")
+		}
+		k.privateKey, k.publicKey = privateKey, &privateKey.PublicKey
+	case jwt.SigningMethodEdDSA:
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return err
+		}
+		edKey, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return errors.New("EdDSA requires an Ed25519 key")
+		}
+		k.privateKey, k.publicKey = edKey, edKey.Public()
+	}
+	return nil
+}
+
+func (k *signingKey) parsePublicKey(data []byte) error {
+	var publicKey crypto.PublicKey
+	var err error
+	switch k.method {
+	case jwt.SigningMethodRS256:
+		publicKey, err = jwt.ParseRSAPublicKeyFromPEM(data)
+	case jwt.SigningMethodES256:
+		var ecKey *ecdsa.PublicKey
+		ecKey, err = jwt.ParseECPublicKeyFromPEM(data)
+		if err == nil && ecKey.Curve != elliptic.P256() {
+			err = errors.New("ES256 requires a P-256 key")
+		}
+		publicKey = ecKey
+	case jwt.SigningMethodEdDSA:
+		publicKey, err = jwt.ParseEdPublicKeyFromPEM(data)
+	}
+	if err != nil {
+		return err
+	}
+	// 同时配置了私钥时, 公钥必须与之匹配
+	if k.publicKey != nil {
+		if equal, ok := k.publicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !equal.Equal(publicKey) {
+			return errors.New("public key does not match private key")
+		}
+	}
+	k.publicKey = publicKey
+	return nil
+}
+
+// isAsymmetric HS256 的密钥不能公开
+func (k *signingKey) isAsymmetric() bool {
+	_, ok := k.publicKey.([]byte)
+	return !ok
+}
@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/enum"
+	"go-server-starter/pkg/utils"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reused, token family revoked")
+	ErrRefreshTokenUsed    = errors.New("refresh token has already been used")
+	ErrRefreshTokenRevoked = errors.New("refresh token family has been revoked")
+)
+
+// TokenPair 访问令牌和刷新令牌
+type TokenPair struct {
+	FamilyID              string
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// IssueTokenPair 登录时签发令牌对, 并开启新的令牌家族
+func (j *JWT) IssueTokenPair(ctx context.Context, uniCode string, deviceType enum.DeviceType) (*TokenPair, error) {
+	familyID, err := utils.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	return j.issueTokenPair(ctx, uniCode, familyID, deviceType, time.Now().UnixMilli())
+}
+
+// RotateRefreshToken 使用刷新令牌换取新的令牌对, 旧的刷新令牌随即失效
+//
+// 已使用过的刷新令牌再次出现时, 说明令牌可能已泄露, 会撤销整个令牌家族
+func (j *JWT) RotateRefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	key := constant.RedisKeyOfRefreshToken(hashRefreshToken(refreshToken))
+	data, err := j.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrRefreshTokenInvalid
+	}
+	familyID := data["familyID"]
+	revoked, err := j.IsFamilyRevoked(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	// 原子标记为已使用, 并发请求中只有一个能成功
+	used, err := j.redis.HIncrBy(ctx, key, "used", 1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if used > 1 {
+		if revoked {
+			return nil, ErrRefreshTokenUsed
+		}
+		if err := j.RevokeFamily(ctx, familyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if revoked {
+		return nil, ErrRefreshTokenRevoked
+	}
+	// 登录时间早于用户的令牌生效时间 (登出所有设备)
+	uniCode := data["uniCode"]
+	authTime, _ := strconv.ParseInt(data["authTime"], 10, 64)
+	validAfter, err := j.getTokensValidAfter(ctx, uniCode)
+	if err != nil {
+		return nil, err
+	}
+	if authTime < validAfter {
+		return nil, ErrRefreshTokenRevoked
+	}
+	status, err := j.GetUserBlock(ctx, uniCode)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		return nil, &UserBlockedError{Status: status}
+	}
+	deviceType := enum.DeviceType(utils.StrToInt(data["deviceType"]))
+	return j.issueTokenPair(ctx, uniCode, familyID, deviceType, authTime)
+}
+
+// issueTokenPair authTime 为令牌家族的登录时间 (unix ms), 轮换时保持不变
+func (j *JWT) issueTokenPair(ctx context.Context, uniCode string, familyID string, deviceType enum.DeviceType, authTime int64) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := j.GenerateAccessToken(uniCode, familyID, deviceType)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	expire := j.config.RefreshTokenExpires.Get(deviceType)
+	key := constant.RedisKeyOfRefreshToken(hashRefreshToken(refreshToken))
+	pipe := j.redis.TxPipeline()
+	pipe.HSet(ctx, key,
+		"uniCode", uniCode,
+		"familyID", familyID,
+		"deviceType", strconv.Itoa(int(deviceType)),
+		"authTime", authTime,
+		"used", 0,
+	)
+	// 已使用的刷新令牌保留到过期, 以便识别重放
+	pipe.Expire(ctx, key, expire)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		FamilyID:              familyID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: time.Now().Add(expire),
+	}, nil
+}
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken redis 中只保存刷新令牌的哈希
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"context"
+	"go-server-starter/internal/constant"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// IsTokenRevoked 判断访问令牌是否已被撤销:
+// - 令牌家族被撤销 (登出, 刷新令牌重放, 会话被踢出)
+// - 令牌本身在黑名单中
+// - 令牌签发时间早于用户的 "令牌生效时间" (登出所有设备)
+func (j *JWT) IsTokenRevoked(ctx context.Context, claims *CustomClaims) (bool, error) {
+	pipe := j.redis.Pipeline()
+	familyRevoked := pipe.Exists(ctx, constant.RedisKeyOfTokenFamilyRevoked(claims.FamilyID))
+	denied := pipe.Exists(ctx, constant.RedisKeyOfTokenDenylist(claims.ID))
+	validAfter := pipe.Get(ctx, constant.RedisKeyOfTokenValidAfter(claims.UniCode))
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return false, err
+	}
+	if familyRevoked.Val() > 0 || denied.Val() > 0 {
+		return true, nil
+	}
+	if validAfter.Err() == nil {
+		validAfterMs, _ := strconv.ParseInt(validAfter.Val(), 10, 64)
+		// 与刷新令牌的 authTime 使用相同的规则: 早于生效时间的令牌失效
+		if claims.IssuedAtMs < validAfterMs {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsFamilyRevoked 判断令牌家族是否已被撤销
+func (j *JWT) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	if familyID == "" {
+		return false, nil
+	}
+	n, err := j.redis.Exists(ctx, constant.RedisKeyOfTokenFamilyRevoked(familyID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RevokeFamily 撤销令牌家族, 该家族下的刷新令牌和访问令牌全部失效
+func (j *JWT) RevokeFamily(ctx context.Context, familyID string) error {
+	// 撤销标记需要比该家族下任何已签发的令牌活得更久
+	return j.redis.Set(ctx, constant.RedisKeyOfTokenFamilyRevoked(familyID), 1, j.config.RefreshTokenExpires.Max()).Err()
+}
+
+// RevokeToken 将访问令牌加入黑名单直到其过期
+func (j *JWT) RevokeToken(ctx context.Context, claims *CustomClaims) error {
+	if claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return j.redis.Set(ctx, constant.RedisKeyOfTokenDenylist(claims.ID), 1, ttl).Err()
+}
+
+// RevokeUserTokens 使用户在此之前签发的所有令牌失效
+func (j *JWT) RevokeUserTokens(ctx context.Context, uniCode string) error {
+	now := time.Now().UnixMilli()
+	return j.redis.Set(ctx, constant.RedisKeyOfTokenValidAfter(uniCode), now, j.config.RefreshTokenExpires.Max()).Err()
+}
+
+// getTokensValidAfter 获取用户的令牌生效时间 (unix ms), 未设置时返回 0
+func (j *JWT) getTokensValidAfter(ctx context.Context, uniCode string) (int64, error) {
+	value, err := j.redis.Get(ctx, constant.RedisKeyOfTokenValidAfter(uniCode)).Int64()
+	if err == goredis.Nil {
+		return 0, nil
+	}
+	return value, err
+}
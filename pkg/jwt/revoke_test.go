@@ -0,0 +1,75 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/enum"
+	"go-server-starter/pkg/jwt"
+	"go-server-starter/pkg/redis"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestJWT(t *testing.T) *jwt.JWT {
+	t.Helper()
+	rds := &redis.Redis{Client: goredis.NewClient(&goredis.Options{Addr: miniredis.RunT(t).Addr()})}
+	expires := config.MultiTokenExpireConfig{Web: time.Hour, Default: time.Hour}
+	j, err := jwt.NewJWT(&config.JWTConfig{Issuer: "test", TokenSecret: "test-secret", AccessTokenExpires: expires, RefreshTokenExpires: expires}, rds, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+func issue(t *testing.T, j *jwt.JWT) (string, *jwt.CustomClaims) {
+	t.Helper()
+	token, _, err := j.GenerateAccessToken("user", "family", enum.DeviceTypeWeb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := j.ParseAndVerifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token, claims
+}
+
+func TestLogoutAllWithinSameSecond(t *testing.T) {
+	j := newTestJWT(t)
+
+	_, before := issue(t, j)
+	time.Sleep(2 * time.Millisecond)
+	if err := j.RevokeUserTokens(t.Context(), "user"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	token, after := issue(t, j)
+
+	if revoked, err := j.IsTokenRevoked(t.Context(), before); err != nil || !revoked {
+		t.Fatalf("token issued before logout-all: revoked = %v, %v", revoked, err)
+	}
+	if revoked, err := j.IsTokenRevoked(t.Context(), after); err != nil || revoked {
+		t.Fatalf("token issued after logout-all: revoked = %v, %v", revoked, err)
+	}
+
+	// 标准时间声明仍然是整数秒
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, claim := range []string{"iat", "exp", "nbf"} {
+		if strings.Contains(string(raw[claim]), ".") {
+			t.Fatalf("%s = %s, want whole seconds", claim, raw[claim])
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// LogNotifier 将通知输出到日志，用于本地开发
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, event Event) error {
+	n.logger.Info("notification",
+		zap.String("type", string(event.Type)),
+		zap.String("uniCode", event.UniCode),
+		zap.Any("data", event.Data),
+	)
+	return nil
+}
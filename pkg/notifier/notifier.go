@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"go-server-starter/internal/config"
+	"go-server-starter/pkg/sender"
+
+	"go.uber.org/zap"
+)
+
+type EventType string
+
+const (
+	EventTypeNewLogin EventType = "new_login" // 新设备或新 IP 段登录
+)
+
+// Event 发给用户的通知事件, Data 为事件相关的字段
+type Event struct {
+	Type    EventType         `json:"type"`
+	UniCode string            `json:"uniCode"`
+	Email   string            `json:"email"`
+	Mobile  string            `json:"mobile"` // 国家码-手机号
+	Locale  string            `json:"locale"`
+	Data    map[string]string `json:"data"`
+}
+
+// Notifier 通知接口，接入站内信、推送或 webhook 时实现该接口即可
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// NewNotifier 根据配置的驱动创建通知器
+func NewNotifier(config config.NotifierConfig, sender sender.Sender, logger *zap.Logger) (Notifier, error) {
+	switch config.Driver {
+	case "", "log":
+		return NewLogNotifier(logger), nil
+	case "sender":
+		return NewSenderNotifier(sender, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier driver: %s", config.Driver)
+	}
+}
@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"go-server-starter/internal/i18n"
+	"go-server-starter/pkg/sender"
+
+	"go.uber.org/zap"
+)
+
+// SenderNotifier 通过邮件发送通知, 没有邮箱时发送短信
+type SenderNotifier struct {
+	sender sender.Sender
+	logger *zap.Logger
+}
+
+func NewSenderNotifier(sender sender.Sender, logger *zap.Logger) *SenderNotifier {
+	return &SenderNotifier{sender: sender, logger: logger}
+}
+
+func (n *SenderNotifier) Notify(ctx context.Context, event Event) error {
+	var subject, content i18n.Text
+	switch event.Type {
+	case EventTypeNewLogin:
+		subject, content = i18n.MsgNewLoginSubject, i18n.MsgNewLoginContent
+	default:
+		return fmt.Errorf("unknown notification type: %s", event.Type)
+	}
+	msg := sender.Message{
+		Subject: subject.T(event.Locale),
+		Content: content.T(event.Locale, event.Data),
+	}
+	switch {
+	case event.Email != "":
+		msg.Channel, msg.To = sender.ChannelEmail, event.Email
+	case event.Mobile != "":
+		msg.Channel, msg.To = sender.ChannelSMS, event.Mobile
+	default:
+		n.logger.Info("notification skipped, no email or mobile", zap.String("type", string(event.Type)), zap.String("uniCode", event.UniCode))
+		return nil
+	}
+	return n.sender.Send(ctx, msg)
+}
@@ -0,0 +1,225 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrProviderNotFound = errors.New("oauth provider not found")
+
+// UserInfo 第三方账号信息, 由 ID Token 或 userinfo 接口映射而来
+type UserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	AvatarURL     string
+}
+
+// Provider 第三方登录提供方, 接入新的协议时实现该接口即可
+type Provider interface {
+	Name() string
+	// AuthCodeURL 生成授权地址, codeChallenge 为 PKCE S256 挑战
+	AuthCodeURL(ctx context.Context, state string, codeChallenge string, nonce string) (string, error)
+	// Exchange 使用授权码换取令牌并返回用户信息
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*UserInfo, error)
+}
+
+// Registry 按名称管理已配置的提供方
+type Registry struct {
+	providers map[string]Provider
+	names     []string
+}
+
+// NewRegistry 根据配置创建提供方
+func NewRegistry(configs []config.OAuthProviderConfig, logger *zap.Logger) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider, len(configs))}
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, providerConfig := range configs {
+		var provider Provider
+		switch providerConfig.Type {
+		case "oidc":
+			if providerConfig.Issuer == "" {
+				return nil, fmt.Errorf("oauth provider %s: issuer is required", providerConfig.Name)
+			}
+			provider = NewOIDCProvider(providerConfig, client, logger.Named(providerConfig.Name))
+		case "oauth2":
+			if providerConfig.AuthURL == "" || providerConfig.TokenURL == "" || providerConfig.UserInfoURL == "" {
+				return nil, fmt.Errorf("oauth provider %s: authUrl, tokenUrl and userInfoUrl are required", providerConfig.Name)
+			}
+			provider = NewOAuth2Provider(providerConfig, client, logger.Named(providerConfig.Name))
+		default:
+			return nil, fmt.Errorf("oauth provider %s: unknown type %q", providerConfig.Name, providerConfig.Type)
+		}
+		if err := r.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register 注册提供方, 名称不能重复
+func (r *Registry) Register(provider Provider) error {
+	if _, ok := r.providers[provider.Name()]; ok {
+		return fmt.Errorf("oauth provider %s already registered", provider.Name())
+	}
+	r.providers[provider.Name()] = provider
+	r.names = append(r.names, provider.Name())
+	return nil
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return provider, nil
+}
+
+// Names 按注册顺序返回提供方名称
+func (r *Registry) Names() []string {
+	return r.names
+}
+
+// GenerateCodeVerifier 生成 PKCE code_verifier
+func GenerateCodeVerifier() (string, error) {
+	return randomString(32)
+}
+
+// CodeChallengeS256 计算 PKCE S256 code_challenge
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// GenerateState 生成 state 或 nonce
+func GenerateState() (string, error) {
+	return randomString(24)
+}
+
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// tokenResponse 令牌接口的响应
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// exchangeCode 授权码换取令牌, 客户端凭证放在请求体中
+func exchangeCode(ctx context.Context, client *http.Client, cfg config.OAuthProviderConfig, tokenURL string, code string, codeVerifier string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	var token tokenResponse
+	if err := doJSON(client, req, &token); err != nil {
+		return nil, err
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("token endpoint: %s %s", token.Error, token.ErrorDesc)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token endpoint: empty access token")
+	}
+	return &token, nil
+}
+
+// fetchUserInfo 使用访问令牌请求用户信息
+func fetchUserInfo(ctx context.Context, client *http.Client, userInfoURL string, accessToken string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	var claims map[string]any
+	if err := doJSON(client, req, &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Host+req.URL.Path, resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// mapUserInfo 按配置的字段名映射用户信息
+func mapUserInfo(cfg config.OAuthProviderConfig, claims map[string]any) (*UserInfo, error) {
+	info := &UserInfo{
+		Subject:   claimString(claims, fieldOr(cfg.SubjectField, "sub")),
+		Email:     strings.ToLower(claimString(claims, fieldOr(cfg.EmailField, "email"))),
+		Name:      claimString(claims, fieldOr(cfg.NameField, "name")),
+		AvatarURL: claimString(claims, fieldOr(cfg.AvatarField, "picture")),
+	}
+	if verified, ok := claims["email_verified"].(bool); ok {
+		info.EmailVerified = verified
+	}
+	if info.Subject == "" {
+		return nil, errors.New("userinfo: subject is empty")
+	}
+	return info, nil
+}
+
+func fieldOr(field string, fallback string) string {
+	if field == "" {
+		return fallback
+	}
+	return field
+}
+
+// claimString 数字类型的 ID (如 GitHub) 也转为字符串
+func claimString(claims map[string]any, key string) string {
+	switch value := claims[key].(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%.0f", value)
+	case json.Number:
+		return value.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,201 @@
+// Package oauthtest 进程内的 OIDC 提供方, 用于测试第三方登录
+//
+// 提供 discovery, JWKS, 授权 (Authorize 模拟用户同意), 令牌和 userinfo 接口, 令牌接口会校验客户端凭证,
+// redirect_uri 和 PKCE. 修改 Claims, UserInfo, Nonce 和 SigningKey 可以模拟不同的用户和异常的提供方.
+package oauthtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"go-server-starter/internal/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClientID     = "test-client"
+	ClientSecret = "test-secret"
+	RedirectURL  = "https://app.example.com/oauth/callback"
+	AccessToken  = "test-access-token"
+	kid          = "test-key"
+)
+
+type Server struct {
+	*httptest.Server
+
+	// Claims ID Token 中的用户声明 (sub, email, email_verified, name, picture)
+	Claims map[string]any
+	// UserInfo userinfo 接口返回的内容
+	UserInfo map[string]any
+	// Nonce 不为空时代替授权请求中的 nonce 写入 ID Token
+	Nonce string
+	// SigningKey 签名 ID Token 的私钥, 默认为 JWKS 中公布的密钥
+	SigningKey *rsa.PrivateKey
+
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+type grant struct {
+	codeChallenge string
+	nonce         string
+	redirectURI   string
+}
+
+// NewServer 启动提供方, 测试结束时关闭
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		Claims:     map[string]any{"sub": "subject-1", "email": "user@example.com", "email_verified": true, "name": "Test User"},
+		UserInfo:   map[string]any{},
+		SigningKey: key,
+		key:        key,
+		grants:     map[string]grant{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("GET /jwks", s.handleJWKS)
+	mux.HandleFunc("POST /token", s.handleToken)
+	mux.HandleFunc("GET /userinfo", s.handleUserInfo)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// OIDCConfig 指向该提供方的 oidc 配置
+func (s *Server) OIDCConfig(name string) config.OAuthProviderConfig {
+	return config.OAuthProviderConfig{
+		Name:         name,
+		Type:         "oidc",
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  RedirectURL,
+		Issuer:       s.URL,
+	}
+}
+
+// OAuth2Config 指向该提供方的 oauth2 配置, 不使用 discovery 和 ID Token
+func (s *Server) OAuth2Config(name string) config.OAuthProviderConfig {
+	return config.OAuthProviderConfig{
+		Name:         name,
+		Type:         "oauth2",
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  RedirectURL,
+		AuthURL:      s.URL + "/authorize",
+		TokenURL:     s.URL + "/token",
+		UserInfoURL:  s.URL + "/userinfo",
+	}
+}
+
+// Authorize 模拟用户在授权地址上同意授权, 返回回调中的 code 和 state
+func (s *Server) Authorize(t testing.TB, authorizeURL string) (code string, state string) {
+	t.Helper()
+	u, err := url.Parse(authorizeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != ClientID || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorize request: %s", authorizeURL)
+	}
+	code = rand.Text()
+	s.mu.Lock()
+	s.grants[code] = grant{
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	s.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"userinfo_endpoint":      s.URL + "/userinfo",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]any{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+	}}})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+		return
+	}
+	// code 只能使用一次
+	s.mu.Lock()
+	g, ok := s.grants[r.PostForm.Get("code")]
+	delete(s.grants, r.PostForm.Get("code"))
+	s.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != g.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+	nonce := g.nonce
+	if s.Nonce != "" {
+		nonce = s.Nonce
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{"iss": s.URL, "aud": ClientID, "iat": now.Unix(), "exp": now.Add(time.Hour).Unix(), "nonce": nonce}
+	for k, v := range s.Claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	idToken, err := token.SignedString(s.SigningKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"access_token": AccessToken, "token_type": "Bearer", "id_token": idToken})
+}
+
+func (s *Server) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	if strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != AccessToken {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_token"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.UserInfo)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
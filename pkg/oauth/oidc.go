@@ -0,0 +1,240 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// OIDCProvider 通过 discovery 获取端点, 并校验 ID Token 的签名, 签发者, 受众和 nonce
+type OIDCProvider struct {
+	config config.OAuthProviderConfig
+	client *http.Client
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]crypto.PublicKey
+	keysAt    time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // 部分提供方返回字符串
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+// 未知 kid 时重新拉取密钥的最小间隔
+const jwksRefreshInterval = time.Minute
+
+func NewOIDCProvider(config config.OAuthProviderConfig, client *http.Client, logger *zap.Logger) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &OIDCProvider{config: config, client: client, logger: logger}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string, codeChallenge string, nonce string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	return buildAuthCodeURL(p.config, discovery.AuthorizationEndpoint, state, codeChallenge, nonce), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*UserInfo, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := exchangeCode(ctx, p.client, p.config, discovery.TokenEndpoint, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token endpoint: missing id_token")
+	}
+	claims, err := p.verifyIDToken(ctx, discovery, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	info := &UserInfo{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+		AvatarURL:     claims.Picture,
+	}
+	// ID Token 中没有邮箱等信息时, 从 userinfo 接口补全
+	if info.Email == "" && discovery.UserInfoEndpoint != "" {
+		userInfoClaims, err := fetchUserInfo(ctx, p.client, discovery.UserInfoEndpoint, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		extra, err := mapUserInfo(p.config, userInfoClaims)
+		if err != nil {
+			return nil, err
+		}
+		// userinfo 的 sub 必须与 ID Token 一致
+		if extra.Subject != info.Subject {
+			return nil, errors.New("userinfo: subject mismatch")
+		}
+		info.Email, info.EmailVerified = extra.Email, extra.EmailVerified
+		if info.Name == "" {
+			info.Name = extra.Name
+		}
+		if info.AvatarURL == "" {
+			info.AvatarURL = extra.AvatarURL
+		}
+	}
+	return info, nil
+}
+
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, discovery *oidcDiscovery, rawIDToken string, nonce string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, discovery, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("id_token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id_token: subject is empty")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id_token: nonce mismatch")
+	}
+	return claims, nil
+}
+
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+	var discovery oidcDiscovery
+	if err := doJSON(p.client, req, &discovery); err != nil {
+		return nil, err
+	}
+	// 防止 discovery 文档被篡改为其他签发者
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("discovery: issuer mismatch %q", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("discovery: missing endpoints")
+	}
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// getKey 获取 kid 对应的公钥, 未知 kid 时重新拉取 (提供方轮换密钥)
+func (p *OIDCProvider) getKey(ctx context.Context, discovery *oidcDiscovery, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(p.keysAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := doJSON(p.client, req, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+			if errN != nil || errE != nil {
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	p.keys, p.keysAt = keys, time.Now()
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	return key, nil
+}
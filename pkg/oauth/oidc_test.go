@@ -0,0 +1,194 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"go-server-starter/pkg/oauth"
+	"go-server-starter/pkg/oauth/oauthtest"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// authorize 走一遍授权流程, 返回提供方回调的 code 以及发起授权时的 code_verifier 和 nonce
+func authorize(t *testing.T, server *oauthtest.Server, provider oauth.Provider) (code, codeVerifier, nonce string) {
+	t.Helper()
+	state, err := oauth.GenerateState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err = oauth.GenerateState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	codeVerifier, err = oauth.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizeURL, err := provider.AuthCodeURL(context.Background(), state, oauth.CodeChallengeS256(codeVerifier), nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, returnedState := server.Authorize(t, authorizeURL)
+	if returnedState != state {
+		t.Fatalf("state = %q, want %q", returnedState, state)
+	}
+	return code, codeVerifier, nonce
+}
+
+func newOIDCProvider(server *oauthtest.Server) *oauth.OIDCProvider {
+	return oauth.NewOIDCProvider(server.OIDCConfig("mock"), &http.Client{Timeout: 5 * time.Second}, zap.NewNop())
+}
+
+func TestOIDCExchange(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	server.Claims["email"] = "User@Example.com"
+	provider := newOIDCProvider(server)
+
+	code, codeVerifier, nonce := authorize(t, server, provider)
+	info, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Subject != "subject-1" || info.Email != "user@example.com" || !info.EmailVerified || info.Name != "Test User" {
+		t.Fatalf("unexpected user info: %+v", info)
+	}
+
+	// code 只能使用一次
+	if _, err := provider.Exchange(context.Background(), code, codeVerifier, nonce); err == nil {
+		t.Fatal("reused code was accepted")
+	}
+}
+
+func TestOIDCExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	provider := newOIDCProvider(server)
+
+	code, _, nonce := authorize(t, server, provider)
+	otherVerifier, err := oauth.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Exchange(context.Background(), code, otherVerifier, nonce); err == nil {
+		t.Fatal("code exchanged with a different code_verifier")
+	}
+}
+
+func TestOIDCExchangeRejectsForgedSignature(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	// 使用相同 kid 但不在 JWKS 中的密钥签名
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.SigningKey = forged
+	provider := newOIDCProvider(server)
+
+	code, codeVerifier, nonce := authorize(t, server, provider)
+	_, err = provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err == nil || !strings.Contains(err.Error(), "id_token") {
+		t.Fatalf("forged id_token error = %v", err)
+	}
+}
+
+func TestOIDCExchangeRejectsNonceMismatch(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	server.Nonce = "replayed-nonce"
+	provider := newOIDCProvider(server)
+
+	code, codeVerifier, nonce := authorize(t, server, provider)
+	_, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err == nil || !strings.Contains(err.Error(), "nonce mismatch") {
+		t.Fatalf("nonce mismatch error = %v", err)
+	}
+}
+
+func TestOIDCExchangeRejectsWrongAudience(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	server.Claims["aud"] = "another-client"
+	provider := newOIDCProvider(server)
+
+	code, codeVerifier, nonce := authorize(t, server, provider)
+	if _, err := provider.Exchange(context.Background(), code, codeVerifier, nonce); err == nil {
+		t.Fatal("id_token for another client was accepted")
+	}
+}
+
+func TestOIDCExchangeFillsEmailFromUserInfo(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	delete(server.Claims, "email")
+	delete(server.Claims, "email_verified")
+	server.UserInfo = map[string]any{"sub": "subject-1", "email": "info@example.com", "email_verified": true}
+	provider := newOIDCProvider(server)
+
+	code, codeVerifier, nonce := authorize(t, server, provider)
+	info, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Email != "info@example.com" || !info.EmailVerified {
+		t.Fatalf("unexpected user info: %+v", info)
+	}
+}
+
+func TestOIDCExchangeRejectsUserInfoSubjectMismatch(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	delete(server.Claims, "email")
+	server.UserInfo = map[string]any{"sub": "someone-else", "email": "victim@example.com", "email_verified": true}
+	provider := newOIDCProvider(server)
+
+	code, codeVerifier, nonce := authorize(t, server, provider)
+	_, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err == nil || !strings.Contains(err.Error(), "subject mismatch") {
+		t.Fatalf("subject mismatch error = %v", err)
+	}
+}
+
+func TestOIDCDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	cfg := server.OIDCConfig("mock")
+	cfg.Issuer = server.URL + "/other"
+	provider := oauth.NewOIDCProvider(cfg, &http.Client{Timeout: 5 * time.Second}, zap.NewNop())
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "challenge", "nonce"); err == nil {
+		t.Fatal("discovery from another issuer was accepted")
+	}
+}
+
+func TestOAuth2ExchangeNeverTrustsEmail(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	server.UserInfo = map[string]any{"id": float64(42), "email": "user@example.com", "email_verified": true, "login": "octocat"}
+	cfg := server.OAuth2Config("github")
+	cfg.SubjectField, cfg.NameField = "id", "login"
+	provider := oauth.NewOAuth2Provider(cfg, &http.Client{Timeout: 5 * time.Second}, zap.NewNop())
+
+	code, codeVerifier, _ := authorize(t, server, provider)
+	info, err := provider.Exchange(context.Background(), code, codeVerifier, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Subject != "42" || info.Name != "octocat" || info.EmailVerified {
+		t.Fatalf("unexpected user info: %+v", info)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	registry, err := oauth.NewRegistry(nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(newOIDCProvider(server)); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(newOIDCProvider(server)); err == nil {
+		t.Fatal("duplicate provider name was accepted")
+	}
+	if _, err := registry.Get("unknown"); err != oauth.ErrProviderNotFound {
+		t.Fatalf("Get(unknown) error = %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-server-starter/internal/config"
+	"go-server-starter/pkg/utils"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmBcrypt   Algorithm = "bcrypt"
+	AlgorithmArgon2id Algorithm = "argon2id"
+)
+
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// Hasher 密码哈希，根据配置选择算法，并能判断已有哈希是否需要升级
+//
+// 哈希格式:
+// - bcrypt: $2a$<cost>$...
+// - argon2id: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type Hasher struct {
+	config config.PasswordConfig
+	dummy  string // 用户不存在时用于比较的哈希
+}
+
+func NewHasher(config config.PasswordConfig) (*Hasher, error) {
+	switch Algorithm(config.Algorithm) {
+	case AlgorithmBcrypt:
+		if config.BcryptCost < bcrypt.MinCost || config.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost: %d", config.BcryptCost)
+		}
+	case AlgorithmArgon2id:
+		if config.Argon2Memory == 0 || config.Argon2Iterations == 0 || config.Argon2Parallelism == 0 || config.Argon2KeyLength == 0 {
+			return nil, errors.New("invalid argon2id params")
+		}
+	default:
+		return nil, fmt.Errorf("unknown password algorithm: %s", config.Algorithm)
+	}
+	h := &Hasher{config: config}
+	salt, err := h.GenerateSalt()
+	if err != nil {
+		return nil, err
+	}
+	if h.dummy, err = h.Hash(salt, salt); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// GenerateSalt 生成新的盐
+func (h *Hasher) GenerateSalt() (string, error) {
+	return utils.GenerateSalt(h.config.SaltSize)
+}
+
+// Hash 使用当前配置的算法生成密码哈希
+func (h *Hasher) Hash(raw, salt string) (string, error) {
+	switch Algorithm(h.config.Algorithm) {
+	case AlgorithmArgon2id:
+		key := argon2.IDKey([]byte(raw), []byte(salt), h.config.Argon2Iterations, h.config.Argon2Memory, h.config.Argon2Parallelism, h.config.Argon2KeyLength)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version,
+			h.config.Argon2Memory,
+			h.config.Argon2Iterations,
+			h.config.Argon2Parallelism,
+			base64.RawStdEncoding.EncodeToString([]byte(salt)),
+			base64.RawStdEncoding.EncodeToString(key),
+		), nil
+	default:
+		hash, err := bcrypt.GenerateFromPassword(bcryptInput(raw, salt), h.config.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+}
+
+// Compare 校验密码，支持所有已知算法，与当前配置无关
+func (h *Hasher) Compare(raw, salt, hash string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		params, saltBytes, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+		other := argon2.IDKey([]byte(raw), saltBytes, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), bcryptInput(raw, salt)) == nil
+}
+
+// bcrypt 只接受 72 字节以内的输入
+const bcryptMaxInput = 72
+
+// bcryptInput 密码加盐后超过 72 字节时 (如含多字节字符的长密码) 先做 SHA-256, 较短的输入保持不变以兼容已有的哈希
+func bcryptInput(raw, salt string) []byte {
+	input := []byte(raw + salt)
+	if len(input) <= bcryptMaxInput {
+		return input
+	}
+	sum := sha256.Sum256(input)
+	return []byte(base64.RawStdEncoding.EncodeToString(sum[:]))
+}
+
+// CompareDummy 与启动时生成的哈希比较, 只用于消耗与 Compare 相同的时间
+func (h *Hasher) CompareDummy(raw string) {
+	h.Compare(raw, "", h.dummy)
+}
+
+// NeedsRehash 判断哈希是否由旧的算法或参数生成，需要在下次登录时重新哈希
+func (h *Hasher) NeedsRehash(hash string) bool {
+	switch Algorithm(h.config.Algorithm) {
+	case AlgorithmArgon2id:
+		params, _, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return true
+		}
+		return params.memory != h.config.Argon2Memory ||
+			params.iterations != h.config.Argon2Iterations ||
+			params.parallelism != h.config.Argon2Parallelism ||
+			uint32(len(key)) != h.config.Argon2KeyLength
+	default:
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return true
+		}
+		return cost != h.config.BcryptCost
+	}
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func decodeArgon2id(hash string) (*argon2Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != string(AlgorithmArgon2id) {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	var params argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	return &params, salt, key, nil
+}
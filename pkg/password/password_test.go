@@ -0,0 +1,55 @@
+package password
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/pkg/utils"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newBcryptHasher(t *testing.T) *Hasher {
+	t.Helper()
+	h, err := NewHasher(config.PasswordConfig{Algorithm: string(AlgorithmBcrypt), BcryptCost: bcrypt.MinCost, SaltSize: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestBcryptLongMultiBytePassword(t *testing.T) {
+	h := newBcryptHasher(t)
+	salt, err := h.GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 48 个字符 (max=48), 144 字节
+	raw := strings.Repeat("密", 48)
+
+	hash, err := h.Hash(raw, salt)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !h.Compare(raw, salt, hash) {
+		t.Fatal("long password does not match its hash")
+	}
+	if h.Compare(strings.Repeat("密", 47)+"码", salt, hash) {
+		t.Fatal("a different long password matched")
+	}
+}
+
+func TestBcryptShortPasswordCompatible(t *testing.T) {
+	h := newBcryptHasher(t)
+	// 之前直接对 raw+salt 做 bcrypt 生成的哈希仍然可以校验
+	hash, err := utils.CryptoHash("password123", "salt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.Compare("password123", "salt", hash) {
+		t.Fatal("existing bcrypt hash no longer matches")
+	}
+	if h.Compare("password124", "salt", hash) {
+		t.Fatal("wrong password matched")
+	}
+}
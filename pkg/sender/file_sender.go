@@ -0,0 +1,52 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FileSender 将消息以 JSON 行的形式追加到文件，用于本地开发和测试
+type FileSender struct {
+	mu     sync.Mutex
+	path   string
+	logger *zap.Logger
+}
+
+func NewFileSender(path string, logger *zap.Logger) (*FileSender, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sender path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create file sender dir failed: %w", err)
+	}
+	return &FileSender{path: path, logger: logger}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time `json:"sentAt"`
+	}{msg, time.Now()})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open file sender file failed: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write file sender file failed: %w", err)
+	}
+	s.logger.Debug("message written", zap.String("channel", string(msg.Channel)), zap.String("to", msg.To), zap.String("file", s.path))
+	return nil
+}
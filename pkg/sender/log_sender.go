@@ -0,0 +1,26 @@
+package sender
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// LogSender 将消息输出到日志，用于本地开发
+type LogSender struct {
+	logger *zap.Logger
+}
+
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Info("message sent",
+		zap.String("channel", string(msg.Channel)),
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("content", msg.Content),
+	)
+	return nil
+}
@@ -0,0 +1,40 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"go-server-starter/internal/config"
+
+	"go.uber.org/zap"
+)
+
+type Channel string
+
+const (
+	ChannelSMS   Channel = "sms"   // 短信
+	ChannelEmail Channel = "email" // 邮件
+)
+
+type Message struct {
+	Channel Channel `json:"channel"`
+	To      string  `json:"to"`
+	Subject string  `json:"subject"`
+	Content string  `json:"content"`
+}
+
+// Sender 消息发送接口，接入短信、邮件服务商时实现该接口即可
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender 根据配置的驱动创建发送器
+func NewSender(config config.SenderConfig, logger *zap.Logger) (Sender, error) {
+	switch config.Driver {
+	case "", "log":
+		return NewLogSender(logger), nil
+	case "file":
+		return NewFileSender(config.FilePath, logger)
+	default:
+		return nil, fmt.Errorf("unknown sender driver: %s", config.Driver)
+	}
+}
@@ -0,0 +1,122 @@
+package tenant
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/internal/ctx"
+	"go-server-starter/internal/enum"
+	"go-server-starter/internal/exception"
+	"go-server-starter/internal/model"
+	"go-server-starter/internal/repo"
+	"go-server-starter/internal/service"
+	"go-server-starter/pkg/jwt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Resolver interface {
+	// Resolve 识别当前组织并确认用户是成员, 需要在认证之后使用
+	Resolve() gin.HandlerFunc
+	// RequireRole 要求用户在当前组织中拥有任一指定角色
+	RequireRole(roles ...enum.OrganizationRole) gin.HandlerFunc
+}
+
+type ResolverImpl struct {
+	service service.Service
+	config  *config.TenantConfig
+	logger  *zap.Logger
+}
+
+func NewResolver(service service.Service, config *config.TenantConfig, logger *zap.Logger) Resolver {
+	return &ResolverImpl{service: service, config: config, logger: logger}
+}
+
+func (r *ResolverImpl) Resolve() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx = ctx.FromGinCtx(c)
+		slug, exc := r.slugFromRequest(c)
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		uniCode, exc := ctx.GetUserUniCode()
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		org, member, exc := r.service.Organization().Resolve(ctx, slug, uniCode)
+		if exc != nil {
+			ctx.ToError(exc)
+			return
+		}
+		// 之后的仓库操作都限定在该组织内
+		c.Request = c.Request.WithContext(repo.WithTenant(c.Request.Context(), org.ID))
+		c.Set(constant.CTX_KEY_OF_TENANT_MEMBER, member)
+		c.Next()
+	}
+}
+
+func (r *ResolverImpl) RequireRole(roles ...enum.OrganizationRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		member, ok := GetMember(c)
+		if !ok {
+			ctx.FromGinCtx(c).ToError(exception.OrganizationTenantRequired)
+			return
+		}
+		if !slices.Contains(roles, member.Role) {
+			ctx.FromGinCtx(c).ToError(exception.OrganizationRoleForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetMember 获取当前用户在当前组织中的成员信息
+func GetMember(c *gin.Context) (*model.OrganizationMember, bool) {
+	value, ok := c.Get(constant.CTX_KEY_OF_TENANT_MEMBER)
+	if !ok {
+		return nil, false
+	}
+	member, ok := value.(*model.OrganizationMember)
+	return member, ok
+}
+
+// slugFromRequest 令牌绑定了组织时, 请求头和子域名只能指向同一个组织
+func (r *ResolverImpl) slugFromRequest(c *gin.Context) (string, *exception.Exception) {
+	var requested string
+	if r.config.Header != "" {
+		requested = strings.ToLower(strings.TrimSpace(c.GetHeader(r.config.Header)))
+	}
+	if requested == "" {
+		requested = r.slugFromHost(c.Request.Host)
+	}
+	if claims, ok := jwt.GetClaims(c); ok && claims.Tenant != "" {
+		if requested != "" && requested != claims.Tenant {
+			return "", exception.OrganizationTenantMismatch
+		}
+		return claims.Tenant, nil
+	}
+	if requested == "" {
+		return "", exception.OrganizationTenantRequired
+	}
+	return requested, nil
+}
+
+// slugFromHost 从 <slug>.<baseDomain> 中取出 slug, 只支持一级子域名
+func (r *ResolverImpl) slugFromHost(host string) string {
+	if r.config.BaseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	sub, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(r.config.BaseDomain))
+	if !ok || sub == "" || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"go-server-starter/internal/config"
+	"go-server-starter/internal/constant"
+	"go-server-starter/pkg/jwt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func newTestGinCtx(host string, header string, tid string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "http://"+host+"/api/org", nil)
+	if header != "" {
+		c.Request.Header.Set("X-Tenant", header)
+	}
+	if tid != "" {
+		c.Set(constant.CTX_KEY_OF_TOKEN_CLAIMS, &jwt.CustomClaims{UniCode: "u1", Tenant: tid})
+	}
+	return c, w
+}
+
+func TestSlugFromRequest(t *testing.T) {
+	r := &ResolverImpl{config: &config.TenantConfig{Header: "X-Tenant", BaseDomain: "example.com"}, logger: zap.NewNop()}
+	tests := []struct {
+		name   string
+		host   string
+		header string
+		tid    string
+		want   string
+	}{
+		{name: "header", host: "api.test", header: "acme", want: "acme"},
+		{name: "subdomain", host: "acme.example.com", want: "acme"},
+		{name: "claim", host: "api.test", tid: "acme", want: "acme"},
+		{name: "claim matches header", host: "api.test", header: "ACME", tid: "acme", want: "acme"},
+	}
+	for _, tt := range tests {
+		c, _ := newTestGinCtx(tt.host, tt.header, tt.tid)
+		got, exc := r.slugFromRequest(c)
+		if exc != nil || got != tt.want {
+			t.Errorf("%s: slugFromRequest = %q, %v, want %q", tt.name, got, exc, tt.want)
+		}
+	}
+}
+
+func TestResolveRejectsOtherTenantThanClaim(t *testing.T) {
+	r := &ResolverImpl{config: &config.TenantConfig{Header: "X-Tenant", BaseDomain: "example.com"}, logger: zap.NewNop()}
+	for _, tt := range []struct{ host, header string }{
+		{host: "api.test", header: "other"},
+		{host: "other.example.com"},
+	} {
+		c, w := newTestGinCtx(tt.host, tt.header, "acme")
+		r.Resolve()(c)
+		if !c.IsAborted() || w.Code != http.StatusForbidden {
+			t.Errorf("host %s header %q: status = %d, want %d", tt.host, tt.header, w.Code, http.StatusForbidden)
+		}
+	}
+}
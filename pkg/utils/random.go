@@ -1,6 +1,8 @@
 package utils
 
 import (
+	_rand "crypto/rand"
+	"math/big"
 	"math/rand/v2"
 	"strings"
 	"time"
@@ -42,3 +44,21 @@ func RandomUserCode() string {
 	}
 	return result.String()
 }
+
+// SecureRandomCode 使用 crypto/rand 生成随机码，用于验证码等安全场景
+func SecureRandomCode(length int, charset ...CharSet) (string, error) {
+	var chars string
+	for _, c := range charset {
+		chars += string(c)
+	}
+	max := big.NewInt(int64(len(chars)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := _rand.Int(_rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = chars[n.Int64()]
+	}
+	return string(code), nil
+}